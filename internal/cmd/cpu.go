@@ -2,7 +2,10 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -19,10 +22,10 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 	var text string
 	
 	if nerdFontFlag {
-		text = fmt.Sprintf("%s %.1f%% %s %d°C %s %.1fGHz %s %d", 
-			nerdfonts.CPUUsage, metrics.Usage, 
-			nerdfonts.CPUTemp, metrics.Temperature, 
-			nerdfonts.CPUFreq, metrics.Frequency, 
+		text = fmt.Sprintf("%s %s%% %s %s°C %s %sGHz %s %d", 
+			nerdfonts.CPUUsage, formatting.Number(metrics.Usage, 1, widestPercent), 
+			nerdfonts.CPUTemp, formatting.Number(float64(metrics.Temperature), 0, widestTemp), 
+			nerdfonts.CPUFreq, formatting.Number(metrics.Frequency, 1, widestFreq), 
 			nerdfonts.CPUCores, metrics.Cores)
 		
 		tooltipLines := []string{
@@ -46,6 +49,23 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 				return fmt.Sprintf("%s System Power: %.1fW", nerdfonts.CPUPower, metrics.Power)
 			}(),
 		}
+		if metrics.PackagePower > 0 {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s Package Power: %s", nerdfonts.CPUPower, formatRAPLPower(metrics)))
+		}
+		if metrics.Throttle.MaxFreq > 0 {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s Throttle: %s", nerdfonts.CPUThrottle, formatThrottleReasons(metrics.Throttle.Reasons)))
+		}
+		if metrics.Tdie > 0 {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s Tdie: %d°C", nerdfonts.CPUTemp, metrics.Tdie))
+		}
+		if len(metrics.CCDTemps) > 0 {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s CCDs: %s", nerdfonts.CPUTemp, formatCCDTemps(metrics.CCDTemps)))
+		}
+		if len(metrics.CoreClasses) > 1 {
+			for _, class := range metrics.CoreClasses {
+				tooltipLines = append(tooltipLines, fmt.Sprintf("%s %s", nerdfonts.CPUCores, formatCoreClass(class)))
+			}
+		}
 		
 		// Add pstate information if flag is enabled and available
 		if withPstateFlag && metrics.PstateStatus != "not_available" {
@@ -60,11 +80,18 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 			if metrics.LowestNonlinearFreq > 0 {
 				pstateLines = append(pstateLines, fmt.Sprintf("%s Lowest Freq: %.2fGHz", nerdfonts.CPULowestNonlinearFreq, metrics.LowestNonlinearFreq))
 			}
+			if top := formatTopPreferredCores(metrics); top != "" {
+				pstateLines = append(pstateLines, fmt.Sprintf("%s Top Cores: %s", nerdfonts.CPUPstatePrefcore, top))
+			}
 			tooltipLines = append(tooltipLines, pstateLines...)
 		}
 		return text, strings.Join(tooltipLines, "\n")
 	}
-	text = fmt.Sprintf("%.1f%% %d°C %.1fGHz %d cores", metrics.Usage, metrics.Temperature, metrics.Frequency, metrics.Cores)
+	text = fmt.Sprintf("%s%% %s°C %sGHz %d cores",
+		formatting.Number(metrics.Usage, 1, widestPercent),
+		formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+		formatting.Number(metrics.Frequency, 1, widestFreq),
+		metrics.Cores)
 	
 	tooltipLines := []string{
 		fmt.Sprintf("Usage: %.1f%%", metrics.Usage),
@@ -87,6 +114,23 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 			return fmt.Sprintf("System Power: %.1fW", metrics.Power)
 		}(),
 	}
+	if metrics.PackagePower > 0 {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("Package Power: %s", formatRAPLPower(metrics)))
+	}
+	if metrics.Throttle.MaxFreq > 0 {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("Throttle: %s", formatThrottleReasons(metrics.Throttle.Reasons)))
+	}
+	if metrics.Tdie > 0 {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("Tdie: %d°C", metrics.Tdie))
+	}
+	if len(metrics.CCDTemps) > 0 {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("CCDs: %s", formatCCDTemps(metrics.CCDTemps)))
+	}
+	if len(metrics.CoreClasses) > 1 {
+		for _, class := range metrics.CoreClasses {
+			tooltipLines = append(tooltipLines, formatCoreClass(class))
+		}
+	}
 	
 	// Add pstate information if flag is enabled and available
 	if withPstateFlag && metrics.PstateStatus != "not_available" {
@@ -101,6 +145,9 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 		if metrics.LowestNonlinearFreq > 0 {
 			pstateLines = append(pstateLines, fmt.Sprintf("Lowest Freq: %.2fGHz", metrics.LowestNonlinearFreq))
 		}
+		if top := formatTopPreferredCores(metrics); top != "" {
+			pstateLines = append(pstateLines, fmt.Sprintf("Top Cores: %s", top))
+		}
 		tooltipLines = append(tooltipLines, pstateLines...)
 	}
 	return text, strings.Join(tooltipLines, "\n")
@@ -109,23 +156,33 @@ func formatCPUWithSymbols(metrics *cpu.Metrics) (string, string) {
 func formatCPUAllMetrics(metrics *cpu.Metrics) string {
 	baseText := ""
 	if nerdFontFlag {
-		baseText = fmt.Sprintf("%s %.1f%% %s %d°C %s %.1fGHz %s %d %s %.1f%% %s %.2f %s %s %s %t %s %.1f-%.1fGHz %s %.1f%% %s %.1fW",
-			nerdfonts.CPUUsage, metrics.Usage,
-			nerdfonts.CPUTemp, metrics.Temperature,
-			nerdfonts.CPUFreq, metrics.Frequency,
+		baseText = fmt.Sprintf("%s %s%% %s %s°C %s %sGHz %s %d %s %s%% %s %s %s %s %s %t %s %.1f-%.1fGHz %s %s%% %s %sW",
+			nerdfonts.CPUUsage, formatting.Number(metrics.Usage, 1, widestPercent),
+			nerdfonts.CPUTemp, formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+			nerdfonts.CPUFreq, formatting.Number(metrics.Frequency, 1, widestFreq),
 			nerdfonts.CPUCores, metrics.Cores,
-			nerdfonts.CPUMemory, metrics.MemoryUsage,
-			nerdfonts.CPULoad, metrics.LoadAvg,
+			nerdfonts.CPUMemory, formatting.Number(metrics.MemoryUsage, 1, widestPercent),
+			nerdfonts.CPULoad, formatting.Number(metrics.LoadAvg, 2, widestCPU()),
 			nerdfonts.CPUGovernor, metrics.Governor,
 			nerdfonts.CPUBoost, metrics.BoostEnabled,
 			nerdfonts.CPUMinMax, metrics.MinFreq, metrics.MaxFreq,
-			nerdfonts.CPUIOwait, metrics.IOWait,
-			nerdfonts.CPUPower, metrics.Power)
+			nerdfonts.CPUIOwait, formatting.Number(metrics.IOWait, 1, widestPercent),
+			nerdfonts.CPUPower, formatting.Number(metrics.Power, 1, -widestBatteryPower))
 	} else {
-		baseText = fmt.Sprintf("%.1f%% %d°C %.1fGHz %d cores %.1f%% memory %.2f load %s %t boost %.1f-%.1fGHz %.1f%% iowait %.1fW system",
-			metrics.Usage, metrics.Temperature, metrics.Frequency, metrics.Cores,
-			metrics.MemoryUsage, metrics.LoadAvg, metrics.Governor, metrics.BoostEnabled,
-			metrics.MinFreq, metrics.MaxFreq, metrics.IOWait, metrics.Power)
+		baseText = fmt.Sprintf("%s%% %s°C %sGHz %d cores %s%% memory %s load %s %t boost %.1f-%.1fGHz %s%% iowait %sW system",
+			formatting.Number(metrics.Usage, 1, widestPercent),
+			formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+			formatting.Number(metrics.Frequency, 1, widestFreq), metrics.Cores,
+			formatting.Number(metrics.MemoryUsage, 1, widestPercent),
+			formatting.Number(metrics.LoadAvg, 2, widestCPU()), metrics.Governor, metrics.BoostEnabled,
+			metrics.MinFreq, metrics.MaxFreq,
+			formatting.Number(metrics.IOWait, 1, widestPercent),
+			formatting.Number(metrics.Power, 1, -widestBatteryPower))
+	}
+	
+	// Hybrid parts also show the frequency of each core class
+	if len(metrics.CoreClasses) > 1 {
+		baseText += " " + formatClassFreqs(metrics.CoreClasses)
 	}
 	
 	// Add pstate information if flag is enabled and available
@@ -145,24 +202,241 @@ func formatCPUAllMetrics(metrics *cpu.Metrics) string {
 }
 
 func formatCPUUsage(usage float64) string {
+	text := formatting.Number(usage, 1, widestPercent)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1f%%", nerdfonts.CPUUsage, usage)
+		return fmt.Sprintf("%s %s%%", nerdfonts.CPUUsage, text)
 	}
-	return fmt.Sprintf("%.1f%%", usage)
+	return fmt.Sprintf("%s%%", text)
+}
+
+// formatCPUThrottle formats the CPU throttle reasons for the text
+func formatCPUThrottle(reasons []string) string {
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.CPUThrottle, formatThrottleReasons(reasons))
+	}
+	return formatThrottleReasons(reasons)
+}
+
+// formatCPUThrottleDetail lists the limits the throttle reasons are derived from
+func formatCPUThrottleDetail(status cpu.ThrottleStatus) string {
+	lines := []string{
+		fmt.Sprintf("Peak Freq: %.2fGHz (cap %.2fGHz, max %.2fGHz)", status.PeakFreq, status.CapFreq, status.MaxFreq),
+	}
+	if status.ThermalLimit > 0 {
+		lines = append(lines, fmt.Sprintf("Tctl: %d°C (limit %d°C)", status.Temperature, status.ThermalLimit))
+	} else {
+		lines = append(lines, fmt.Sprintf("Tctl: %d°C", status.Temperature))
+	}
+	if status.ThrottleEvents > 0 {
+		lines = append(lines, fmt.Sprintf("Throttle Events: %d since boot", status.ThrottleEvents))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// cpuThrottleClass returns the Waybar classes, flagging active throttling
+// after the base class
+func cpuThrottleClass(reasons []string, metrics *cpu.Metrics) []string {
+	classes := cpuClass("custom-cpu", metrics)
+	if len(reasons) > 0 {
+		classes = slices.Insert(classes, 1, classThrottling)
+	}
+	return classes
+}
+
+// formatUsageBreakdown lists the share of every CPU state
+func formatUsageBreakdown(metrics *cpu.Metrics) string {
+	header := "Breakdown:"
+	if nerdFontFlag {
+		header = fmt.Sprintf("%s Breakdown:", nerdfonts.CPUUsage)
+	}
+
+	return strings.Join([]string{
+		header,
+		fmt.Sprintf("  User: %.1f%%", metrics.User),
+		fmt.Sprintf("  Nice: %.1f%%", metrics.Nice),
+		fmt.Sprintf("  System: %.1f%%", metrics.System),
+		fmt.Sprintf("  IRQ: %.1f%%", metrics.IRQ),
+		fmt.Sprintf("  SoftIRQ: %.1f%%", metrics.SoftIRQ),
+		fmt.Sprintf("  IO Wait: %.1f%%", metrics.IOWait),
+		fmt.Sprintf("  Steal: %.1f%%", metrics.Steal),
+		fmt.Sprintf("  Guest: %.1f%%", metrics.Guest+metrics.GuestNice),
+	}, "\n")
+}
+
+// coreBars are the block characters of the per-core usage bar, lowest first
+var coreBars = []rune("▁▂▃▄▅▆▇█")
+
+// formatCoreBar renders one block character per logical CPU
+func formatCoreBar(cores []cpu.CoreStat) string {
+	bar := make([]rune, 0, len(cores))
+	for _, core := range cores {
+		level := min(int(core.Usage/100*float64(len(coreBars))), len(coreBars)-1)
+		bar = append(bar, coreBars[max(level, 0)])
+	}
+	return string(bar)
+}
+
+// formatCoresDetail formats the per-core text according to --core-view
+func formatCoresDetail(cores []cpu.CoreStat) string {
+	var icon nerdfonts.Icon
+	var text string
+	switch coreViewFlag {
+	case "busiest":
+		busiest := cpu.BusiestCore(cores)
+		icon, text = nerdfonts.CPUUsage, fmt.Sprintf("cpu%s %s%%",
+			formatting.Number(float64(busiest.CPU), 0, widestCPUID()), formatting.Number(busiest.Usage, 0, widestPercent))
+	case "max":
+		fastest := cpu.FastestCore(cores)
+		icon, text = nerdfonts.CPUFreq, fmt.Sprintf("cpu%s %sGHz",
+			formatting.Number(float64(fastest.CPU), 0, widestCPUID()), formatting.Number(fastest.Frequency, 1, widestFreq))
+	default:
+		icon, text = nerdfonts.CPUUsage, formatCoreBar(cores)
+	}
+
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", icon, text)
+	}
+	return text
+}
+
+// formatCoresTooltip lists usage and frequency of every logical CPU
+func formatCoresTooltip(cores []cpu.CoreStat) string {
+	lines := make([]string, 0, len(cores))
+	for _, core := range cores {
+		lines = append(lines, fmt.Sprintf("cpu%d: %5.1f%% %.2fGHz", core.CPU, core.Usage, core.Frequency))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sensorTemp reads one labelled temperature sensor
+func sensorTemp(sensor string) (int, error) {
+	sensors, err := cpuField("temp_sensors", cpu.GetTempSensors, func(m *cpu.Metrics) cpu.TempSensors { return m.TempSensors })
+	if err != nil {
+		return 0, err
+	}
+	return sensors.Sensor(sensor)
+}
+
+// formatCCDTemps lists CCD temperatures as "ccd1 55°C, ccd2 61°C"
+func formatCCDTemps(ccds []cpu.CCDTemp) string {
+	parts := make([]string, 0, len(ccds))
+	for _, ccd := range ccds {
+		parts = append(parts, fmt.Sprintf("ccd%d %d°C", ccd.CCD, ccd.Temperature))
+	}
+	return strings.Join(parts, ", ")
 }
 
 func formatCPUTemp(temp int) string {
+	text := formatting.Number(float64(temp), 0, widestTemp)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d°C", nerdfonts.CPUTemp, temp)
+		return fmt.Sprintf("%s %s°C", nerdfonts.CPUTemp, text)
 	}
-	return fmt.Sprintf("%d°C", temp)
+	return fmt.Sprintf("%s°C", text)
 }
 
 func formatCPUFreq(freq float64) string {
+	text := formatting.Number(freq, 1, widestFreq)
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %sGHz", nerdfonts.CPUFreq, text)
+	}
+	return fmt.Sprintf("%sGHz", text)
+}
+
+// formatCPUFreqClasses formats the frequency of each core class for the text
+func formatCPUFreqClasses(classes []cpu.CoreClass) string {
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1fGHz", nerdfonts.CPUFreq, freq)
+		return fmt.Sprintf("%s %s", nerdfonts.CPUFreq, formatClassFreqs(classes))
+	}
+	return formatClassFreqs(classes)
+}
+
+// formatClassFreqs formats core class frequencies as "Zen5: 4.9GHz, Zen5c: 3.3GHz"
+func formatClassFreqs(classes []cpu.CoreClass) string {
+	parts := make([]string, 0, len(classes))
+	for _, class := range classes {
+		parts = append(parts, fmt.Sprintf("%s: %sGHz", class.Name, formatting.Number(class.Frequency, 1, widestFreq)))
 	}
-	return fmt.Sprintf("%.1fGHz", freq)
+	return strings.Join(parts, ", ")
+}
+
+// formatCoreClass formats the load of a core class for the tooltip
+func formatCoreClass(class cpu.CoreClass) string {
+	line := fmt.Sprintf("%s (cpu%s): %.1f%% %.1fGHz", class.Name, formatCPURanges(class.CPUs), class.Usage, class.Frequency)
+	if class.Temperature > 0 {
+		line += fmt.Sprintf(" %d°C", class.Temperature)
+	}
+	return line
+}
+
+// formatDomainName names a CCD or CCX, e.g. "CCD1"
+func formatDomainName(domain cpu.DomainStat) string {
+	level := cpu.DomainCCD
+	if perDomainFlag == cpu.DomainCCX {
+		level = cpu.DomainCCX
+	}
+	return fmt.Sprintf("%s%d", strings.ToUpper(level), domain.Domain)
+}
+
+// formatDomainUsage formats per-domain usage as "CCD1: 45.0%, CCD2: 3.0%"
+func formatDomainUsage(domains []cpu.DomainStat) string {
+	parts := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		parts = append(parts, fmt.Sprintf("%s: %s%%", formatDomainName(domain), formatting.Number(domain.Usage, 1, widestPercent)))
+	}
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.CPUUsage, strings.Join(parts, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDomainFreq formats per-domain frequency as "CCD1: 5.1GHz, CCD2: 4.8GHz"
+func formatDomainFreq(domains []cpu.DomainStat) string {
+	parts := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		parts = append(parts, fmt.Sprintf("%s: %sGHz", formatDomainName(domain), formatting.Number(domain.Frequency, 1, widestFreq)))
+	}
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.CPUFreq, strings.Join(parts, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatDomainsTooltip lists the CPUs, load and L3 of every CCD or CCX
+func formatDomainsTooltip(domains []cpu.DomainStat) string {
+	lines := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		line := fmt.Sprintf("%s (cpu%s): %.1f%% %.2fGHz (peak %.2fGHz)",
+			formatDomainName(domain), formatCPURanges(domain.CPUs), domain.Usage, domain.Frequency, domain.PeakFreq)
+		if domain.Temperature > 0 {
+			line += fmt.Sprintf(" %d°C", domain.Temperature)
+		}
+		if domain.L3Size > 0 {
+			line += fmt.Sprintf(" L3 %dMB", domain.L3Size/1024)
+		}
+		if domain.VCache {
+			line += " V-Cache"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatCPURanges formats sorted CPU numbers as ranges, e.g. "0-3,8-11"
+func formatCPURanges(cpus []int) string {
+	var parts []string
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d", cpus[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
 }
 
 func formatCPUCores(cores int) string {
@@ -173,17 +447,19 @@ func formatCPUCores(cores int) string {
 }
 
 func formatCPUMemory(memory float64) string {
+	text := formatting.Number(memory, 1, widestPercent)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1f%%", nerdfonts.CPUMemory, memory)
+		return fmt.Sprintf("%s %s%%", nerdfonts.CPUMemory, text)
 	}
-	return fmt.Sprintf("%.1f%%", memory)
+	return fmt.Sprintf("%s%%", text)
 }
 
 func formatCPULoad(load float64) string {
+	text := formatting.Number(load, 2, widestCPU())
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.2f", nerdfonts.CPULoad, load)
+		return fmt.Sprintf("%s %s", nerdfonts.CPULoad, text)
 	}
-	return fmt.Sprintf("%.2f", load)
+	return text
 }
 
 func formatCPUGovernor(governor string) string {
@@ -213,10 +489,27 @@ func formatCPUMinMaxFreq(minFreq, maxFreq float64) string {
 }
 
 func formatCPUIOWait(iowait float64) string {
+	text := formatting.Number(iowait, 1, widestPercent)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1f%%", nerdfonts.CPUIOwait, iowait)
+		return fmt.Sprintf("%s %s%%", nerdfonts.CPUIOwait, text)
 	}
-	return fmt.Sprintf("%.1f%%", iowait)
+	return fmt.Sprintf("%s%%", text)
+}
+
+// formatRAPLPower formats package power, with the core domain when exposed
+func formatRAPLPower(metrics *cpu.Metrics) string {
+	if metrics.CorePower > 0 {
+		return fmt.Sprintf("%.1fW (core %.1fW)", metrics.PackagePower, metrics.CorePower)
+	}
+	return fmt.Sprintf("%.1fW", metrics.PackagePower)
+}
+
+func formatCPUPackagePower(power float64) string {
+	text := formatting.Number(power, 1, widestPackagePower)
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %sW", nerdfonts.CPUPower, text)
+	}
+	return fmt.Sprintf("%sW", text)
 }
 
 func formatCPUPower(power float64) string {
@@ -233,10 +526,13 @@ func formatCPUPower(power float64) string {
 		action = ""
 	}
 	
+	// Sign and action are padded so charging and discharging keep one width
+	text := formatting.Pad(fmt.Sprintf("%s%.1f", sign, power), len(fmt.Sprintf("+%.1f", float64(widestBatteryPower)))) +
+		"W" + formatting.PadRight(action, len(" discharging"))
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %s%.1fW%s", nerdfonts.CPUPower, sign, power, action)
+		return fmt.Sprintf("%s %s", nerdfonts.CPUPower, text)
 	}
-	return fmt.Sprintf("%s%.1fW%s", sign, power, action)
+	return text
 }
 
 func formatPstateStatus(status string) string {
@@ -246,6 +542,107 @@ func formatPstateStatus(status string) string {
 	return fmt.Sprintf("Pstate: %s", status)
 }
 
+// formatCPUList formats the CPUs of a policy as "cpu0" or "cpu0,cpu1"
+func formatCPUList(cpus []int) string {
+	names := make([]string, 0, len(cpus))
+	for _, id := range cpus {
+		names = append(names, fmt.Sprintf("cpu%d", id))
+	}
+	return strings.Join(names, ",")
+}
+
+// topPreferredCores is the number of best-ranked cores shown in the pstate tooltip
+const topPreferredCores = 3
+
+// formatTopPreferredCores names the best-ranked cores, or "" without a ranking
+func formatTopPreferredCores(metrics *cpu.Metrics) string {
+	preferred := cpu.PreferredCores(metrics.Policies, metrics.CoreStats)
+	if len(preferred) == 0 {
+		return ""
+	}
+
+	names := []string{}
+	for _, core := range preferred[:min(len(preferred), topPreferredCores)] {
+		names = append(names, fmt.Sprintf("cpu%d", core.CPU))
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatPreferredCore(core cpu.PreferredCore) string {
+	text := fmt.Sprintf("cpu%s %sGHz %s%%",
+		formatting.Number(float64(core.CPU), 0, widestCPUID()),
+		formatting.Number(core.Frequency, 1, widestFreq),
+		formatting.Number(core.Usage, 0, widestPercent))
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.CPUPstatePrefcore, text)
+	}
+	return text
+}
+
+// formatPreferredCores lists cores by ranking and names the busiest ones
+func formatPreferredCores(preferred []cpu.PreferredCore) string {
+	lines := []string{"Ranking:"}
+	for _, core := range preferred {
+		lines = append(lines, fmt.Sprintf("  cpu%d: rank %d, %.2fGHz, %.1f%%", core.CPU, core.Ranking, core.Frequency, core.Usage))
+	}
+
+	busiest := slices.Clone(preferred)
+	sort.SliceStable(busiest, func(i, j int) bool {
+		return busiest[i].Usage > busiest[j].Usage
+	})
+	names := []string{}
+	for _, core := range busiest[:min(len(busiest), topPreferredCores)] {
+		position := slices.IndexFunc(preferred, func(c cpu.PreferredCore) bool { return c.CPU == core.CPU }) + 1
+		names = append(names, fmt.Sprintf("cpu%d (#%d) %.0f%% %.1fGHz", core.CPU, position, core.Usage, core.Frequency))
+	}
+	lines = append(lines, "Busiest: "+strings.Join(names, ", "))
+
+	return strings.Join(lines, "\n")
+}
+
+// maxRankingShown caps the preferred-core ranking line in the tooltip
+const maxRankingShown = 8
+
+// formatPolicyPstates lists the pstate values of every policy, the
+// preferred-core ranking order and EPP mismatches
+func formatPolicyPstates(policies []cpu.PolicyPstate) string {
+	lines := []string{"Policies:"}
+	for _, policy := range policies {
+		line := fmt.Sprintf("  policy%d (%s): %s", policy.Policy, formatCPUList(policy.CPUs), policy.EnergyPerfPreference)
+		if policy.HighestPerf > 0 {
+			line += fmt.Sprintf(", highest %d", policy.HighestPerf)
+		}
+		if policy.LowestNonlinearFreq > 0 {
+			line += fmt.Sprintf(", lowest %.2fGHz", policy.LowestNonlinearFreq)
+		}
+		if policy.PrefcoreRanking >= 0 {
+			line += fmt.Sprintf(", rank %d", policy.PrefcoreRanking)
+		}
+		lines = append(lines, line)
+	}
+
+	if ranked := cpu.PreferredCores(policies, nil); len(ranked) > 0 {
+		order := []string{}
+		for _, core := range ranked[:min(len(ranked), maxRankingShown)] {
+			order = append(order, fmt.Sprintf("cpu%d", core.CPU))
+		}
+		if len(ranked) > maxRankingShown {
+			order = append(order, "…")
+		}
+		lines = append(lines, "Prefcore Ranking: "+strings.Join(order, " > "))
+	}
+
+	if values := cpu.EnergyPerfPreferences(policies); len(values) > 1 {
+		warning := "EPP mismatch: " + strings.Join(values, ", ")
+		if nerdFontFlag {
+			warning = fmt.Sprintf("%s %s", nerdfonts.CPUThrottle, warning)
+		}
+		lines = append(lines, warning)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 func formatEnergyPerfPreference(energyPerf string) string {
 	if nerdFontFlag {
 		return fmt.Sprintf("%s %s", nerdfonts.CPUEnergyPerfPref, energyPerf)
@@ -253,6 +650,13 @@ func formatEnergyPerfPreference(energyPerf string) string {
 	return fmt.Sprintf("Energy Perf: %s", energyPerf)
 }
 
+var (
+	tempSensorFlag string
+	coreViewFlag   string
+	breakdownFlag  bool
+	perDomainFlag  string
+)
+
 var cpuCmd = &cobra.Command{
 	Use:   "cpu",
 	Short: "AMD CPU monitoring commands",
@@ -267,7 +671,7 @@ var cpuAllCmd = &cobra.Command{
 			return
 		}
 
-		metrics, err := cpu.GetAllMetrics()
+		metrics, err := cpuMetrics()
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -282,7 +686,8 @@ var cpuAllCmd = &cobra.Command{
 		case jsonFormat:
 			text := formatCPUAllMetrics(metrics)
 			_, tooltip := formatCPUWithSymbols(metrics)
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), metrics.Usage, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUAllMetrics(metrics))
 		}
@@ -292,12 +697,15 @@ var cpuAllCmd = &cobra.Command{
 var cpuUsageCmd = &cobra.Command{
 	Use:   "usage",
 	Short: "Get CPU usage percentage",
+	PreRunE: func(_ *cobra.Command, _ []string) error {
+		return cpu.ValidateDomain(perDomainFlag)
+	},
 	Run: func(_ *cobra.Command, _ []string) {
 		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
 			return
 		}
 
-		usage, err := cpu.GetUsage()
+		usage, err := cpuValue(cpu.GetUsage, func(m *cpu.Metrics) float64 { return m.Usage })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -311,7 +719,7 @@ var cpuUsageCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -319,9 +727,23 @@ var cpuUsageCmd = &cobra.Command{
 			
 			text := formatCPUUsage(usage)
 			_, tooltip := formatCPUWithSymbols(metrics)
+			if breakdownFlag {
+				tooltip += "\n\n" + formatUsageBreakdown(metrics)
+			}
+			if domains := metrics.Domains(perDomainFlag); perDomainFlag != "" && len(domains) > 0 {
+				text = formatDomainUsage(domains)
+				tooltip += "\n\n" + formatDomainsTooltip(domains)
+			}
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), usage, cpuAlt(metrics), noTooltipFlag)
 		default:
+			if perDomainFlag != "" {
+				if domains, err := cpu.GetDomainStats(perDomainFlag); err == nil {
+					fmt.Println(formatDomainUsage(domains))
+					return
+				}
+			}
 			fmt.Println(formatCPUUsage(usage))
 		}
 	},
@@ -330,11 +752,23 @@ var cpuUsageCmd = &cobra.Command{
 var cpuTempCmd = &cobra.Command{
 	Use:   "temp",
 	Short: "Get CPU temperature",
+	PreRunE: func(_ *cobra.Command, _ []string) error {
+		if tempSensorFlag == "" {
+			return nil
+		}
+		return cpu.ValidateSensor(tempSensorFlag)
+	},
 	Run: func(_ *cobra.Command, _ []string) {
 		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
 			return
 		}
-		temp, err := cpu.GetTemperature()
+		var temp int
+		var err error
+		if tempSensorFlag != "" {
+			temp, err = sensorTemp(tempSensorFlag)
+		} else {
+			temp, err = cpuValue(cpu.GetTemperature, func(m *cpu.Metrics) int { return m.Temperature })
+		}
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -348,7 +782,7 @@ var cpuTempCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -357,7 +791,8 @@ var cpuTempCmd = &cobra.Command{
 			text := formatCPUTemp(temp)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), percentOf(float64(temp), cpuTempMax(metrics)), cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUTemp(temp))
 		}
@@ -367,11 +802,14 @@ var cpuTempCmd = &cobra.Command{
 var cpuFreqCmd = &cobra.Command{
 	Use:   "freq",
 	Short: "Get CPU frequency",
+	PreRunE: func(_ *cobra.Command, _ []string) error {
+		return cpu.ValidateDomain(perDomainFlag)
+	},
 	Run: func(_ *cobra.Command, _ []string) {
 		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
 			return
 		}
-		freq, err := cpu.GetFrequency()
+		freq, err := cpuValue(cpu.GetFrequency, func(m *cpu.Metrics) float64 { return m.Frequency })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -381,22 +819,41 @@ var cpuFreqCmd = &cobra.Command{
 			}
 			return
 		}
+		
+		text := formatCPUFreq(freq)
+		// Hybrid parts show each core class, an average hides the slow cores
+		classes, err := cpuField("core_classes", cpu.GetCoreClasses, func(m *cpu.Metrics) []cpu.CoreClass { return m.CoreClasses })
+		if err == nil && len(classes) > 1 {
+			text = formatCPUFreqClasses(classes)
+		}
+		
+		var domains []cpu.DomainStat
+		if perDomainFlag != "" {
+			domains, err = cpuValue(func() ([]cpu.DomainStat, error) { return cpu.GetDomainStats(perDomainFlag) },
+				func(m *cpu.Metrics) []cpu.DomainStat { return m.Domains(perDomainFlag) })
+			if err == nil && len(domains) > 0 {
+				text = formatDomainFreq(domains)
+			}
+		}
 
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
 			}
 			
-			text := formatCPUFreq(freq)
 			_, tooltip := formatCPUWithSymbols(metrics)
+			if len(domains) > 0 {
+				tooltip += "\n\n" + formatDomainsTooltip(domains)
+			}
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), percentOf(freq, metrics.MaxFreq), cpuAlt(metrics), noTooltipFlag)
 		default:
-			fmt.Println(formatCPUFreq(freq))
+			fmt.Println(text)
 		}
 	},
 }
@@ -409,7 +866,7 @@ var cpuCoresCmd = &cobra.Command{
 			return
 		}
 
-		cores, err := cpu.GetCores()
+		cores, err := cpuValue(cpu.GetCores, func(m *cpu.Metrics) int { return m.Cores })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -423,7 +880,7 @@ var cpuCoresCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -432,7 +889,8 @@ var cpuCoresCmd = &cobra.Command{
 			text := formatCPUCores(cores)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUCores(cores))
 		}
@@ -447,7 +905,7 @@ var cpuMemoryCmd = &cobra.Command{
 			return
 		}
 
-		memory, err := cpu.GetMemoryUsage()
+		memory, err := cpuField("memory_usage", cpu.GetMemoryUsage, func(m *cpu.Metrics) float64 { return m.MemoryUsage })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -461,7 +919,7 @@ var cpuMemoryCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -470,7 +928,8 @@ var cpuMemoryCmd = &cobra.Command{
 			text := formatCPUMemory(memory)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), memory, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUMemory(memory))
 		}
@@ -485,7 +944,7 @@ var cpuLoadCmd = &cobra.Command{
 			return
 		}
 
-		load, err := cpu.GetLoadAverage()
+		load, err := cpuField("load_avg", cpu.GetLoadAverage, func(m *cpu.Metrics) float64 { return m.LoadAvg })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -499,7 +958,7 @@ var cpuLoadCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -508,7 +967,8 @@ var cpuLoadCmd = &cobra.Command{
 			text := formatCPULoad(load)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), percentOf(load, float64(metrics.Cores)), cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPULoad(load))
 		}
@@ -523,7 +983,7 @@ var cpuGovernorCmd = &cobra.Command{
 			return
 		}
 
-		governor, err := cpu.GetGovernor()
+		governor, err := cpuField("governor", cpu.GetGovernor, func(m *cpu.Metrics) string { return m.Governor })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -537,7 +997,7 @@ var cpuGovernorCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -546,7 +1006,8 @@ var cpuGovernorCmd = &cobra.Command{
 			text := formatCPUGovernor(governor)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, governor, noTooltipFlag)
 		default:
 			fmt.Println(formatCPUGovernor(governor))
 		}
@@ -561,7 +1022,7 @@ var cpuBoostCmd = &cobra.Command{
 			return
 		}
 
-		boost, err := cpu.GetBoostEnabled()
+		boost, err := cpuField("boost_enabled", cpu.GetBoostEnabled, func(m *cpu.Metrics) bool { return m.BoostEnabled })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -575,7 +1036,7 @@ var cpuBoostCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -584,7 +1045,8 @@ var cpuBoostCmd = &cobra.Command{
 			text := formatCPUBoost(boost)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, formatBoostAlt(boost), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUBoost(boost))
 		}
@@ -599,7 +1061,7 @@ var cpuMinFreqCmd = &cobra.Command{
 			return
 		}
 
-		minFreq, _, err := cpu.GetMinMaxFreq()
+		minFreq, err := cpuField("min_freq", cpu.GetMinFreq, func(m *cpu.Metrics) float64 { return m.MinFreq })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -613,7 +1075,7 @@ var cpuMinFreqCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -622,7 +1084,8 @@ var cpuMinFreqCmd = &cobra.Command{
 			text := formatCPUFreq(minFreq)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUFreq(minFreq))
 		}
@@ -637,7 +1100,7 @@ var cpuMaxFreqCmd = &cobra.Command{
 			return
 		}
 
-		_, maxFreq, err := cpu.GetMinMaxFreq()
+		maxFreq, err := cpuField("max_freq", cpu.GetMaxFreq, func(m *cpu.Metrics) float64 { return m.MaxFreq })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -651,7 +1114,7 @@ var cpuMaxFreqCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -660,7 +1123,8 @@ var cpuMaxFreqCmd = &cobra.Command{
 			text := formatCPUFreq(maxFreq)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUFreq(maxFreq))
 		}
@@ -675,7 +1139,7 @@ var cpuIOWaitCmd = &cobra.Command{
 			return
 		}
 
-		iowait, err := cpu.GetIOWait()
+		iowait, err := cpuField("io_wait", cpu.GetIOWait, func(m *cpu.Metrics) float64 { return m.IOWait })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -689,7 +1153,7 @@ var cpuIOWaitCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -698,7 +1162,8 @@ var cpuIOWaitCmd = &cobra.Command{
 			text := formatCPUIOWait(iowait)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), iowait, cpuAlt(metrics), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUIOWait(iowait))
 		}
@@ -713,7 +1178,7 @@ var cpuPowerCmd = &cobra.Command{
 			return
 		}
 
-		power, err := cpu.GetPower()
+		power, err := cpuField("power", cpu.GetPower, func(m *cpu.Metrics) float64 { return m.Power })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -727,7 +1192,7 @@ var cpuPowerCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -736,13 +1201,167 @@ var cpuPowerCmd = &cobra.Command{
 			text := formatCPUPower(power)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, formatPowerAlt(power), noTooltipFlag)
 		default:
 			fmt.Println(formatCPUPower(power))
 		}
 	},
 }
 
+var cpuPackagePowerCmd = &cobra.Command{
+	Use:   "package-power",
+	Short: "Get CPU package power from RAPL energy counters",
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		power, err := cpuField("package_power", cpu.GetPackagePower, func(m *cpu.Metrics) float64 { return m.PackagePower })
+		if err != nil {
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			// Get all metrics for tooltip
+			metrics, err := cpuMetrics()
+			if err != nil {
+				fmt.Println("{}")
+				return
+			}
+			
+			text := formatCPUPackagePower(power)
+			_, tooltip := formatCPUWithSymbols(metrics)
+			
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, cpuAlt(metrics), noTooltipFlag)
+		default:
+			fmt.Println(formatCPUPackagePower(power))
+		}
+	},
+}
+
+var cpuCoresDetailCmd = &cobra.Command{
+	Use:   "cores-detail",
+	Short: "Get per-core CPU usage and frequency",
+	PreRunE: func(_ *cobra.Command, _ []string) error {
+		switch coreViewFlag {
+		case "bar", "busiest", "max":
+			return nil
+		default:
+			return errors.New("invalid --core-view " + coreViewFlag + ", expected bar, busiest or max")
+		}
+	},
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		cores, err := cpuField("core_stats", cpu.GetCoreStats, func(m *cpu.Metrics) []cpu.CoreStat { return m.CoreStats })
+		if err != nil || len(cores) == 0 {
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			text := formatCoresDetail(cores)
+			tooltip := formatCoresTooltip(cores)
+			
+			text, tooltip = applyFormats(text, tooltip, map[string]any{"cores": cores})
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", nil), cpu.BusiestCore(cores).Usage, "", noTooltipFlag)
+		default:
+			fmt.Println(formatCoresDetail(cores))
+		}
+	},
+}
+
+var cpuThrottleCmd = &cobra.Command{
+	Use:   "throttle",
+	Short: "Get CPU throttle reasons (thermal, power limit, user cap)",
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		status, err := cpuField("throttle", cpu.GetThrottleStatus, func(m *cpu.Metrics) cpu.ThrottleStatus { return m.Throttle })
+		if err != nil || status.Reasons == nil {
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			// Get all metrics for tooltip
+			metrics, err := cpuMetrics()
+			if err != nil {
+				fmt.Println("{}")
+				return
+			}
+			
+			text := formatCPUThrottle(status.Reasons)
+			_, tooltip := formatCPUWithSymbols(metrics)
+			tooltip += "\n\n" + formatCPUThrottleDetail(status)
+			
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuThrottleClass(status.Reasons, metrics), -1, formatThrottleAlt(status.Reasons), noTooltipFlag)
+		default:
+			fmt.Println(formatCPUThrottle(status.Reasons))
+		}
+	},
+}
+
+var cpuPrefcoreCmd = &cobra.Command{
+	Use:   "prefcore",
+	Short: "Get cores by preferred-core ranking with their current load",
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		preferred, err := cpuValue(cpu.GetPreferredCores, func(m *cpu.Metrics) []cpu.PreferredCore {
+			return cpu.PreferredCores(m.Policies, m.CoreStats)
+		})
+		if err != nil || len(preferred) == 0 {
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			text := formatPreferredCore(preferred[0])
+			tooltip := formatPreferredCores(preferred)
+			
+			text, tooltip = applyFormats(text, tooltip, map[string]any{"cores": preferred})
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", nil), preferred[0].Usage, "", noTooltipFlag)
+		default:
+			fmt.Println(formatPreferredCore(preferred[0]))
+		}
+	},
+}
+
 var cpuPstateStatusCmd = &cobra.Command{
 	Use:   "pstate-status",
 	Short: "Get AMD pstate driver status",
@@ -751,7 +1370,7 @@ var cpuPstateStatusCmd = &cobra.Command{
 			return
 		}
 
-		status, err := cpu.GetPstateStatus()
+		status, err := cpuField("pstate_status", cpu.GetPstateStatus, func(m *cpu.Metrics) string { return m.PstateStatus })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -765,7 +1384,7 @@ var cpuPstateStatusCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -774,7 +1393,8 @@ var cpuPstateStatusCmd = &cobra.Command{
 			text := formatPstateStatus(status)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, status, noTooltipFlag)
 		default:
 			fmt.Println(formatPstateStatus(status))
 		}
@@ -789,7 +1409,7 @@ var cpuEnergyPerfCmd = &cobra.Command{
 			return
 		}
 
-		energyPerf, err := cpu.GetEnergyPerfPreference()
+		energyPerf, err := cpuField("energy_perf_preference", cpu.GetEnergyPerfPreference, func(m *cpu.Metrics) string { return m.EnergyPerfPreference })
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -803,7 +1423,7 @@ var cpuEnergyPerfCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -812,7 +1432,8 @@ var cpuEnergyPerfCmd = &cobra.Command{
 			text := formatEnergyPerfPreference(energyPerf)
 			_, tooltip := formatCPUWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-cpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, cpuClass("custom-cpu", metrics), -1, energyPerf, noTooltipFlag)
 		default:
 			fmt.Println(formatEnergyPerfPreference(energyPerf))
 		}
@@ -828,11 +1449,15 @@ var cpuPstateCmd = &cobra.Command{
 		}
 
 		// Get all pstate metrics
-		status, _ := cpu.GetPstateStatus()
-		prefcore, _ := cpu.GetPstatePrefcore()
-		energyPerf, _ := cpu.GetEnergyPerfPreference()
-		highestPerf, _ := cpu.GetHighestPerf()
-		lowestFreq, _ := cpu.GetLowestNonlinearFreq()
+		status, _ := cpuField("pstate_status", cpu.GetPstateStatus, func(m *cpu.Metrics) string { return m.PstateStatus })
+		prefcore, _ := cpuField("pstate_prefcore", cpu.GetPstatePrefcore, func(m *cpu.Metrics) string { return m.PstatePrefcore })
+		energyPerf, _ := cpuField("energy_perf_preference", cpu.GetEnergyPerfPreference, func(m *cpu.Metrics) string { return m.EnergyPerfPreference })
+		highestPerf, _ := cpuField("highest_perf", cpu.GetHighestPerf, func(m *cpu.Metrics) int { return m.HighestPerf })
+		lowestFreq, _ := cpuField("lowest_nonlinear_freq", cpu.GetLowestNonlinearFreq, func(m *cpu.Metrics) float64 { return m.LowestNonlinearFreq })
+		policies, _ := cpuField("policies", cpu.GetPolicyPstates, func(m *cpu.Metrics) []cpu.PolicyPstate { return m.Policies })
+		if len(cpu.EnergyPerfPreferences(policies)) > 1 {
+			energyPerf = "mixed"
+		}
 
 		var pstateText string
 		if nerdFontFlag {
@@ -850,14 +1475,18 @@ var cpuPstateCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := cpu.GetAllMetrics()
+			metrics, err := cpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
 			}
 			
 			_, tooltip := formatCPUWithSymbols(metrics)
-			formatting.FormatJSONOutput(pstateText, tooltip, "custom-cpu", noTooltipFlag)
+			if len(policies) > 0 {
+				tooltip += "\n\n" + formatPolicyPstates(policies)
+			}
+			pstateText, tooltip = applyFormats(pstateText, tooltip, metrics)
+			formatting.FormatJSONOutput(pstateText, tooltip, cpuClass("custom-cpu", metrics), -1, status, noTooltipFlag)
 		default:
 			fmt.Println(pstateText)
 		}
@@ -865,11 +1494,18 @@ var cpuPstateCmd = &cobra.Command{
 }
 
 func init() {
+	cpuUsageCmd.Flags().StringVar(&perDomainFlag, "per", "", "Aggregate per ccd or ccx instead of the whole CPU")
+	cpuFreqCmd.Flags().StringVar(&perDomainFlag, "per", "", "Aggregate per ccd or ccx instead of the whole CPU")
+	cpuUsageCmd.Flags().BoolVar(&breakdownFlag, "breakdown", false, "Add the user/nice/system/irq/softirq/iowait/steal/guest breakdown to the tooltip")
+	cpuCoresDetailCmd.Flags().StringVar(&coreViewFlag, "core-view", "bar", "Text view: bar (per-core usage bar), busiest (busiest core) or max (fastest core)")
+	cpuTempCmd.Flags().StringVar(&tempSensorFlag, "sensor", "", "Temperature sensor: tctl, tdie, ccd1..ccd12 or max (hottest CCD)")
+
 	cpuCmd.AddCommand(cpuAllCmd)
 	cpuCmd.AddCommand(cpuUsageCmd)
 	cpuCmd.AddCommand(cpuTempCmd)
 	cpuCmd.AddCommand(cpuFreqCmd)
 	cpuCmd.AddCommand(cpuCoresCmd)
+	cpuCmd.AddCommand(cpuCoresDetailCmd)
 	cpuCmd.AddCommand(cpuMemoryCmd)
 	cpuCmd.AddCommand(cpuLoadCmd)
 	cpuCmd.AddCommand(cpuGovernorCmd)
@@ -878,7 +1514,10 @@ func init() {
 	cpuCmd.AddCommand(cpuMaxFreqCmd)
 	cpuCmd.AddCommand(cpuIOWaitCmd)
 	cpuCmd.AddCommand(cpuPowerCmd)
+	cpuCmd.AddCommand(cpuPackagePowerCmd)
+	cpuCmd.AddCommand(cpuThrottleCmd)
 	cpuCmd.AddCommand(cpuPstateStatusCmd)
 	cpuCmd.AddCommand(cpuEnergyPerfCmd)
 	cpuCmd.AddCommand(cpuPstateCmd)
+	cpuCmd.AddCommand(cpuPrefcoreCmd)
 }
\ No newline at end of file
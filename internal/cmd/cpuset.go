@@ -0,0 +1,73 @@
+// Package cmd provides CLI commands for controlling AMD CPU power management
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bnema/waybar-amd-module/internal/cpu"
+	"github.com/spf13/cobra"
+)
+
+var cpuSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Change AMD pstate and boost settings (requires root)",
+	Long:  "Write energy performance preference, amd_pstate mode or boost across every cpufreq policy. Meant for Waybar on-click actions, run through pkexec or a polkit rule when not root.",
+}
+
+var cpuSetEPPCmd = &cobra.Command{
+	Use:   "epp <preference|next>",
+	Short: "Set the energy performance preference on every policy",
+	Long:  "Set energy_performance_preference on every cpufreq policy. Values are checked against energy_performance_available_preferences; next cycles to the following preference.",
+	Args:  cobra.ExactArgs(1),
+	// Permission errors are not usage errors
+	SilenceUsage: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		preference, err := cpu.SetEnergyPerfPreference(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(preference)
+		return nil
+	},
+}
+
+var cpuSetModeCmd = &cobra.Command{
+	Use:          "mode <" + strings.Join(cpu.PstateModes, "|") + "|next>",
+	Short:        "Switch the amd_pstate driver mode",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		mode, err := cpu.SetPstateStatus(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(mode)
+		return nil
+	},
+}
+
+var cpuSetBoostCmd = &cobra.Command{
+	Use:          "boost <on|off|toggle>",
+	Short:        "Enable, disable or toggle CPU boost",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(_ *cobra.Command, args []string) error {
+		enabled, err := cpu.SetBoost(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(formatCPUBoost(enabled))
+		return nil
+	},
+}
+
+func init() {
+	cpuSetCmd.AddCommand(cpuSetEPPCmd)
+	cpuSetCmd.AddCommand(cpuSetModeCmd)
+	cpuSetCmd.AddCommand(cpuSetBoostCmd)
+	cpuCmd.AddCommand(cpuSetCmd)
+}
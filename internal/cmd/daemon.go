@@ -0,0 +1,139 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/bnema/waybar-amd-module/internal/cpu"
+	"github.com/bnema/waybar-amd-module/internal/daemon"
+	"github.com/bnema/waybar-amd-module/internal/gpu"
+	"github.com/spf13/cobra"
+)
+
+var (
+	noDaemonFlag bool
+
+	daemonSnapshot *daemon.Snapshot
+	daemonFetched  bool
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run the shared metrics sampler",
+	Long:  "Sample all CPU and GPU metrics once per interval and serve them over a Unix socket, so every module reads the same sample instead of polling sysfs itself",
+	RunE: func(_ *cobra.Command, _ []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		return daemon.NewServer(watchInterval()).Run(ctx)
+	},
+}
+
+// fetchDaemonSnapshot returns the daemon snapshot for the current run, or nil
+// when no daemon is reachable. The result is cached until resetDaemonSnapshot.
+func fetchDaemonSnapshot() *daemon.Snapshot {
+	if noDaemonFlag {
+		return nil
+	}
+
+	if !daemonFetched {
+		daemonFetched = true
+		if snapshot, err := daemon.Fetch(); err == nil {
+			daemonSnapshot = snapshot
+		}
+	}
+
+	return daemonSnapshot
+}
+
+// resetDaemonSnapshot forces the next lookup to query the daemon again
+func resetDaemonSnapshot() {
+	daemonSnapshot = nil
+	daemonFetched = false
+}
+
+// cpuMetrics returns CPU metrics from the daemon, falling back to direct sysfs reads
+func cpuMetrics() (*cpu.Metrics, error) {
+	if snapshot := fetchDaemonSnapshot(); snapshot != nil && snapshot.CPU != nil {
+		return snapshot.CPU, nil
+	}
+	return cpu.GetAllMetrics()
+}
+
+// daemonGPU returns the daemon metrics of the selected GPU, or nil
+func daemonGPU() *gpu.Metrics {
+	snapshot := fetchDaemonSnapshot()
+	if snapshot == nil || gpu.Selected() == nil {
+		return nil
+	}
+	return snapshot.GPU(gpu.Selected().PCI)
+}
+
+// gpuMetrics returns GPU metrics from the daemon, falling back to direct sysfs reads
+func gpuMetrics() (*gpu.Metrics, error) {
+	if metrics := daemonGPU(); metrics != nil {
+		return metrics, nil
+	}
+	return gpu.GetAllMetrics()
+}
+
+// allGPUMetrics returns the metrics of every GPU from the daemon, falling back to direct sysfs reads
+func allGPUMetrics() ([]*gpu.Metrics, error) {
+	if snapshot := fetchDaemonSnapshot(); snapshot != nil && len(snapshot.GPUs) > 0 {
+		return snapshot.GPUs, nil
+	}
+	return gpu.GetAllDevicesMetrics()
+}
+
+// cpuValue picks a single CPU metric from the daemon, or reads it directly
+func cpuValue[T any](direct func() (T, error), pick func(*cpu.Metrics) T) (T, error) {
+	if snapshot := fetchDaemonSnapshot(); snapshot != nil && snapshot.CPU != nil {
+		return pick(snapshot.CPU), nil
+	}
+	return direct()
+}
+
+// cpuField is cpuValue for an optional field, returning the daemon's error
+// when it could not read the field instead of its zero value
+func cpuField[T any](field string, direct func() (T, error), pick func(*cpu.Metrics) T) (T, error) {
+	if snapshot := fetchDaemonSnapshot(); snapshot != nil && snapshot.CPU != nil {
+		if err := snapshot.CPU.Err(field); err != nil {
+			var zero T
+			return zero, err
+		}
+		return pick(snapshot.CPU), nil
+	}
+	return direct()
+}
+
+// gpuValue picks a single GPU metric from the daemon, or reads it directly
+func gpuValue[T any](direct func() (T, error), pick func(*gpu.Metrics) T) (T, error) {
+	if metrics := daemonGPU(); metrics != nil {
+		if metrics.Suspended {
+			var zero T
+			return zero, gpu.ErrSuspended
+		}
+		return pick(metrics), nil
+	}
+	return direct()
+}
+
+// gpuField is gpuValue for an optional field, returning the daemon's error
+// when it could not read the field instead of its zero value
+func gpuField[T any](field string, direct func() (T, error), pick func(*gpu.Metrics) T) (T, error) {
+	if metrics := daemonGPU(); metrics != nil {
+		if metrics.Suspended {
+			var zero T
+			return zero, gpu.ErrSuspended
+		}
+		if err := metrics.Err(field); err != nil {
+			var zero T
+			return zero, err
+		}
+		return pick(metrics), nil
+	}
+	return direct()
+}
@@ -2,7 +2,11 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -15,11 +19,11 @@ func formatWithSymbols(metrics *gpu.Metrics) (string, string) {
 	var text string
 	
 	if nerdFontFlag {
-		text = fmt.Sprintf("%s %.1fW %s %d°C %s %.1fGHz %s %d%%", 
-			nerdfonts.GPUPower, metrics.Power, 
-			nerdfonts.GPUTemp, metrics.Temperature, 
-			nerdfonts.GPUFreq, metrics.Frequency, 
-			nerdfonts.GPUUtil, metrics.Utilization)
+		text = fmt.Sprintf("%s %sW %s %s°C %s %sGHz %s %s%%", 
+			nerdfonts.GPUPower, formatting.Number(metrics.Power, 1, widestGPUPower(metrics.PowerCap)), 
+			nerdfonts.GPUTemp, formatting.Number(float64(metrics.Temperature), 0, widestTemp), 
+			nerdfonts.GPUFreq, formatting.Number(metrics.Frequency, 1, widestFreq), 
+			nerdfonts.GPUUtil, formatting.Number(float64(metrics.Utilization), 0, widestPercent))
 		
 		tooltipLines := []string{
 			fmt.Sprintf("%s Power: %.1fW", nerdfonts.GPUPower, metrics.Power),
@@ -33,9 +37,20 @@ func formatWithSymbols(metrics *gpu.Metrics) (string, string) {
 			fmt.Sprintf("%s Memory Temp: %d°C", nerdfonts.GPUTemp, metrics.MemoryTemp),
 			fmt.Sprintf("%s Power Cap: %.1fW", nerdfonts.GPUPower, metrics.PowerCap),
 		}
+		tooltipLines = append(tooltipLines, formatMemoryPools(metrics)...)
+		if metrics.EngineSource != "" {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s Engines: %s", nerdfonts.GPUUtil, formatEngines(metrics.EngineUtilization)))
+		}
+		if metrics.Throttle != nil {
+			tooltipLines = append(tooltipLines, fmt.Sprintf("%s Throttle: %s", nerdfonts.GPUThrottle, formatThrottleReasons(metrics.Throttle)))
+		}
 		return text, strings.Join(tooltipLines, "\n")
 	}
-	text = fmt.Sprintf("%.1fW %d°C %.1fGHz %d%%", metrics.Power, metrics.Temperature, metrics.Frequency, metrics.Utilization)
+	text = fmt.Sprintf("%sW %s°C %sGHz %s%%",
+		formatting.Number(metrics.Power, 1, widestGPUPower(metrics.PowerCap)),
+		formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+		formatting.Number(metrics.Frequency, 1, widestFreq),
+		formatting.Number(float64(metrics.Utilization), 0, widestPercent))
 	
 	tooltipLines := []string{
 		fmt.Sprintf("Power: %.1fW", metrics.Power),
@@ -49,90 +64,356 @@ func formatWithSymbols(metrics *gpu.Metrics) (string, string) {
 		fmt.Sprintf("Memory Temp: %d°C", metrics.MemoryTemp),
 		fmt.Sprintf("Power Cap: %.1fW", metrics.PowerCap),
 	}
+	tooltipLines = append(tooltipLines, formatMemoryPools(metrics)...)
+	if metrics.EngineSource != "" {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("Engines: %s", formatEngines(metrics.EngineUtilization)))
+	}
+	if metrics.Throttle != nil {
+		tooltipLines = append(tooltipLines, fmt.Sprintf("Throttle: %s", formatThrottleReasons(metrics.Throttle)))
+	}
 	return text, strings.Join(tooltipLines, "\n")
 }
 
 func formatGPUAllMetrics(metrics *gpu.Metrics) string {
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1fW %s %d°C %s %.1fGHz %s %d%% %s %.1f%% %s %d RPM %s %.2fV %s %d°C %s %d°C %s %.1fW",
-			nerdfonts.GPUPower, metrics.Power,
-			nerdfonts.GPUTemp, metrics.Temperature,
-			nerdfonts.GPUFreq, metrics.Frequency,
-			nerdfonts.GPUUtil, metrics.Utilization,
-			nerdfonts.GPUMemory, metrics.MemoryUsage,
-			nerdfonts.GPUFan, metrics.FanSpeed,
-			nerdfonts.GPUVoltage, metrics.Voltage,
-			nerdfonts.GPUTemp, metrics.JunctionTemp,
-			nerdfonts.GPUTemp, metrics.MemoryTemp,
+		return fmt.Sprintf("%s %sW %s %s°C %s %sGHz %s %s%% %s %s%% %s %s RPM %s %sV %s %s°C %s %s°C %s %.1fW",
+			nerdfonts.GPUPower, formatting.Number(metrics.Power, 1, widestGPUPower(metrics.PowerCap)),
+			nerdfonts.GPUTemp, formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+			nerdfonts.GPUFreq, formatting.Number(metrics.Frequency, 1, widestFreq),
+			nerdfonts.GPUUtil, formatting.Number(float64(metrics.Utilization), 0, widestPercent),
+			nerdfonts.GPUMemory, formatting.Number(metrics.MemoryUsage, 1, widestPercent),
+			nerdfonts.GPUFan, formatting.Number(float64(metrics.FanSpeed), 0, widestFan),
+			nerdfonts.GPUVoltage, formatting.Number(metrics.Voltage, 2, widestVoltage),
+			nerdfonts.GPUTemp, formatting.Number(float64(metrics.JunctionTemp), 0, widestTemp),
+			nerdfonts.GPUTemp, formatting.Number(float64(metrics.MemoryTemp), 0, widestTemp),
 			nerdfonts.GPUPower, metrics.PowerCap)
 	}
-	return fmt.Sprintf("%.1fW %d°C %.1fGHz %d%% util %.1f%% memory %d RPM %.2fV %d°C junction %d°C memtemp %.1fW cap",
-		metrics.Power, metrics.Temperature, metrics.Frequency, metrics.Utilization,
-		metrics.MemoryUsage, metrics.FanSpeed, metrics.Voltage, metrics.JunctionTemp,
-		metrics.MemoryTemp, metrics.PowerCap)
+	return fmt.Sprintf("%sW %s°C %sGHz %s%% util %s%% memory %s RPM %sV %s°C junction %s°C memtemp %.1fW cap",
+		formatting.Number(metrics.Power, 1, widestGPUPower(metrics.PowerCap)),
+		formatting.Number(float64(metrics.Temperature), 0, widestTemp),
+		formatting.Number(metrics.Frequency, 1, widestFreq),
+		formatting.Number(float64(metrics.Utilization), 0, widestPercent),
+		formatting.Number(metrics.MemoryUsage, 1, widestPercent),
+		formatting.Number(float64(metrics.FanSpeed), 0, widestFan),
+		formatting.Number(metrics.Voltage, 2, widestVoltage),
+		formatting.Number(float64(metrics.JunctionTemp), 0, widestTemp),
+		formatting.Number(float64(metrics.MemoryTemp), 0, widestTemp),
+		metrics.PowerCap)
+}
+
+// formatThrottleReasons joins throttle reasons for display
+func formatThrottleReasons(reasons []string) string {
+	if len(reasons) == 0 {
+		return "none"
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func formatThrottle(reasons []string) string {
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.GPUThrottle, formatThrottleReasons(reasons))
+	}
+	return formatThrottleReasons(reasons)
+}
+
+// engineOrder lists the fdinfo engines in display order, others follow alphabetically
+var engineOrder = []string{"gfx", "compute", "enc", "dec", "jpeg", "dma"}
+
+// formatBytes formats a byte count using binary units
+func formatBytes(bytes uint64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", float64(bytes)/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.0f MiB", float64(bytes)/(1<<20))
+	default:
+		return fmt.Sprintf("%.0f KiB", float64(bytes)/(1<<10))
+	}
+}
+
+// formatProcess formats one process line: busy engines and VRAM
+func formatProcess(process gpu.ProcessUsage) string {
+	engines := make([]string, 0, len(process.Engines))
+	for engine := range process.Engines {
+		engines = append(engines, engine)
+	}
+	sort.Slice(engines, func(i, j int) bool {
+		ri, rj := slices.Index(engineOrder, engines[i]), slices.Index(engineOrder, engines[j])
+		if ri < 0 {
+			ri = len(engineOrder)
+		}
+		if rj < 0 {
+			rj = len(engineOrder)
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return engines[i] < engines[j]
+	})
+
+	parts := []string{}
+	for _, engine := range engines {
+		if percent := process.Engines[engine]; percent >= 0.1 {
+			parts = append(parts, fmt.Sprintf("%s %.1f%%", engine, percent))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "idle")
+	}
+
+	return fmt.Sprintf("%s (%d): %s, %s VRAM", process.Name, process.PID, strings.Join(parts, " "), formatBytes(process.VRAM))
+}
+
+// formatProcessList formats the tooltip section listing GPU processes
+func formatProcessList(processes []gpu.ProcessUsage) string {
+	header := "Processes:"
+	if nerdFontFlag {
+		header = fmt.Sprintf("%s Processes:", nerdfonts.GPUUtil)
+	}
+
+	lines := []string{header}
+	for _, process := range processes {
+		lines = append(lines, "  "+formatProcess(process))
+	}
+	if len(processes) == 0 {
+		lines = append(lines, "  none")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatTopProcess formats the busiest process, or idle when nothing uses the GPU
+func formatTopProcess(processes []gpu.ProcessUsage) string {
+	text := "idle"
+	if len(processes) > 0 && processes[0].Busy() >= 0.1 {
+		text = fmt.Sprintf("%s %s%%", processes[0].Name, formatting.Number(min(processes[0].Busy(), 100), 0, widestPercent))
+	}
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.GPUUtil, text)
+	}
+	return text
+}
+
+// engineUtil reads the utilization of one engine, rounded to a percentage
+func engineUtil(engine string) (int, error) {
+	value, err := gpuValue(func() (float64, error) { return gpu.GetEngine(engine) }, func(m *gpu.Metrics) float64 {
+		value, err := m.Engine(engine)
+		if err != nil {
+			return -1
+		}
+		return value
+	})
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, errors.New(engine + " utilization not available from the daemon")
+	}
+
+	return int(math.Round(value)), nil
+}
+
+// formatEngines formats the per-engine utilization, skipping unknown engines
+func formatEngines(engines gpu.EngineUtilization) string {
+	parts := []string{}
+	for _, engine := range []struct {
+		name  string
+		value float64
+	}{
+		{"gfx", engines.GfxUtil},
+		{"compute", engines.ComputeUtil},
+		{"enc", engines.EncodeUtil},
+		{"dec", engines.DecodeUtil},
+		{"video", engines.VideoUtil},
+		{"dma", engines.DMAUtil},
+	} {
+		if engine.value >= 0 {
+			parts = append(parts, fmt.Sprintf("%s %.0f%%", engine.name, engine.value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatSuspended formats the text shown while the GPU is asleep
+func formatSuspended() string {
+	if nerdFontFlag {
+		return fmt.Sprintf("%s off", nerdfonts.GPUAsleep)
+	}
+	return "off"
 }
 
-func formatPower(power float64) string {
+// printSuspended reports a runtime-suspended GPU without waking it up
+func printSuspended() {
+	text := formatSuspended()
+	switch formatFlag {
+	case jsonFormat:
+		formatting.FormatJSONOutput(text, "GPU is asleep (runtime suspended)", []string{"suspended"}, -1, "suspended", noTooltipFlag)
+	default:
+		fmt.Println(text)
+	}
+}
+
+// gpuLabel returns a short identifier for a GPU in multi-GPU output
+func gpuLabel(metrics *gpu.Metrics) string {
+	kind := "dGPU"
+	if metrics.Integrated {
+		kind = "iGPU"
+	}
+	if metrics.Card != "" {
+		return kind + " " + metrics.Card
+	}
+	return kind + " " + metrics.PCI
+}
+
+// formatAllGPUs formats the text and tooltip listing every GPU
+func formatAllGPUs(all []*gpu.Metrics) (string, string) {
+	texts := make([]string, 0, len(all))
+	sections := make([]string, 0, len(all))
+
+	for _, metrics := range all {
+		text, tooltip := formatWithSymbols(metrics)
+		if metrics.Suspended {
+			text, tooltip = formatSuspended(), "Asleep (runtime suspended)"
+		}
+		texts = append(texts, fmt.Sprintf("%s: %s", gpuLabel(metrics), text))
+		header := fmt.Sprintf("%s (%s, %s)", metrics.Name, gpuLabel(metrics), metrics.PCI)
+		sections = append(sections, header+"\n"+tooltip)
+	}
+
+	return strings.Join(texts, " | "), strings.Join(sections, "\n\n")
+}
+
+func formatPower(power, powerCap float64) string {
+	text := formatting.Number(power, 1, widestGPUPower(powerCap))
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1fW", nerdfonts.GPUPower, power)
+		return fmt.Sprintf("%s %sW", nerdfonts.GPUPower, text)
 	}
-	return fmt.Sprintf("%.1fW", power)
+	return fmt.Sprintf("%sW", text)
 }
 
 func formatTemp(temp int) string {
+	text := formatting.Number(float64(temp), 0, widestTemp)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d°C", nerdfonts.GPUTemp, temp)
+		return fmt.Sprintf("%s %s°C", nerdfonts.GPUTemp, text)
 	}
-	return fmt.Sprintf("%d°C", temp)
+	return fmt.Sprintf("%s°C", text)
 }
 
 func formatFreq(freq float64) string {
+	text := formatting.Number(freq, 1, widestFreq)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1fGHz", nerdfonts.GPUFreq, freq)
+		return fmt.Sprintf("%s %sGHz", nerdfonts.GPUFreq, text)
 	}
-	return fmt.Sprintf("%.1fGHz", freq)
+	return fmt.Sprintf("%sGHz", text)
 }
 
 func formatUtil(util int) string {
+	text := formatting.Number(float64(util), 0, widestPercent)
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s%%", nerdfonts.GPUUtil, text)
+	}
+	return fmt.Sprintf("%s%%", text)
+}
+
+// formatMemorySize formats used/total bytes in the unit of the total
+func formatMemorySize(info gpu.MemoryInfo) string {
+	// Used is padded to the width of the total in fixed-width mode
+	if info.Total >= 1<<30 {
+		total := float64(info.Total) / (1 << 30)
+		return fmt.Sprintf("%s/%.1f GiB", formatting.Number(float64(info.Used)/(1<<30), 1, total), total)
+	}
+	total := float64(info.Total) / (1 << 20)
+	return fmt.Sprintf("%s/%.0f MiB", formatting.Number(float64(info.Used)/(1<<20), 0, total), total)
+}
+
+// formatMemoryPool formats a memory pool according to --memory-unit
+func formatMemoryPool(info gpu.MemoryInfo) string {
+	var text string
+	switch memoryUnitFlag {
+	case "bytes":
+		text = formatMemorySize(info)
+	case "both":
+		text = fmt.Sprintf("%s (%s%%)", formatMemorySize(info), formatting.Number(info.Percent(), 1, widestPercent))
+	default:
+		return formatMemory(info.Percent())
+	}
+
+	if nerdFontFlag {
+		return fmt.Sprintf("%s %s", nerdfonts.GPUMemory, text)
+	}
+	return text
+}
+
+// validateMemoryUnit checks the --memory-unit flag
+func validateMemoryUnit(_ *cobra.Command, _ []string) error {
+	switch memoryUnitFlag {
+	case "percent", "bytes", "both":
+		return nil
+	default:
+		return errors.New("invalid --memory-unit " + memoryUnitFlag + ", expected percent, bytes or both")
+	}
+}
+
+// formatMemoryPools formats the VRAM and GTT tooltip lines, skipping
+// pools the GPU does not report
+func formatMemoryPools(metrics *gpu.Metrics) []string {
+	prefix := ""
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d%%", nerdfonts.GPUUtil, util)
+		prefix = string(nerdfonts.GPUMemory) + " "
+	}
+
+	lines := []string{}
+	if metrics.VRAM.Total > 0 {
+		line := fmt.Sprintf("%sVRAM: %s", prefix, formatMemorySize(metrics.VRAM))
+		if metrics.VisibleVRAM.Total > 0 && metrics.VisibleVRAM.Total < metrics.VRAM.Total {
+			line += fmt.Sprintf(" (visible %s)", formatMemorySize(metrics.VisibleVRAM))
+		}
+		lines = append(lines, line)
+	}
+	if metrics.GTT.Total > 0 {
+		lines = append(lines, fmt.Sprintf("%sGTT: %s", prefix, formatMemorySize(metrics.GTT)))
 	}
-	return fmt.Sprintf("%d%%", util)
+	return lines
 }
 
 func formatMemory(memory float64) string {
+	text := formatting.Number(memory, 1, widestPercent)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.1f%%", nerdfonts.GPUMemory, memory)
+		return fmt.Sprintf("%s %s%%", nerdfonts.GPUMemory, text)
 	}
-	return fmt.Sprintf("%.1f%%", memory)
+	return fmt.Sprintf("%s%%", text)
 }
 
 func formatFan(fan int) string {
+	text := formatting.Number(float64(fan), 0, widestFan)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d RPM", nerdfonts.GPUFan, fan)
+		return fmt.Sprintf("%s %s RPM", nerdfonts.GPUFan, text)
 	}
-	return fmt.Sprintf("%d RPM", fan)
+	return fmt.Sprintf("%s RPM", text)
 }
 
 func formatVoltage(voltage float64) string {
+	text := formatting.Number(voltage, 2, widestVoltage)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %.2fV", nerdfonts.GPUVoltage, voltage)
+		return fmt.Sprintf("%s %sV", nerdfonts.GPUVoltage, text)
 	}
-	return fmt.Sprintf("%.2fV", voltage)
+	return fmt.Sprintf("%sV", text)
 }
 
 func formatJunctionTemp(temp int) string {
+	text := formatting.Number(float64(temp), 0, widestTemp)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d°C", nerdfonts.GPUTemp, temp)
+		return fmt.Sprintf("%s %s°C", nerdfonts.GPUTemp, text)
 	}
-	return fmt.Sprintf("%d°C (junction)", temp)
+	return fmt.Sprintf("%s°C (junction)", text)
 }
 
 func formatMemoryTemp(temp int) string {
+	text := formatting.Number(float64(temp), 0, widestTemp)
 	if nerdFontFlag {
-		return fmt.Sprintf("%s %d°C", nerdfonts.GPUTemp, temp)
+		return fmt.Sprintf("%s %s°C", nerdfonts.GPUTemp, text)
 	}
-	return fmt.Sprintf("%d°C (memory)", temp)
+	return fmt.Sprintf("%s°C (memory)", text)
 }
 
 func formatPowerCap(powerCap float64) string {
@@ -143,10 +424,22 @@ func formatPowerCap(powerCap float64) string {
 }
 
 
+var (
+	gpuSelectFlag  string
+	allGPUsFlag    bool
+	topCountFlag   int
+	utilTopFlag    int
+	utilEngineFlag string
+	memoryUnitFlag string
+)
+
 var gpuCmd = &cobra.Command{
 	Use:   "gpu",
 	Short: "AMD GPU monitoring commands",
 	Long:  "Monitor AMD GPU power, temperature, frequency, and utilization",
+	PersistentPreRunE: func(_ *cobra.Command, _ []string) error {
+		return gpu.Select(gpuSelectFlag)
+	},
 }
 
 var gpuAllCmd = &cobra.Command{
@@ -157,7 +450,30 @@ var gpuAllCmd = &cobra.Command{
 			return
 		}
 
-		metrics, err := gpu.GetAllMetrics()
+		if allGPUsFlag {
+			all, err := allGPUMetrics()
+			if err != nil {
+				switch formatFlag {
+				case jsonFormat:
+					fmt.Println("{}")
+				default:
+					return
+				}
+				return
+			}
+
+			text, tooltip := formatAllGPUs(all)
+			switch formatFlag {
+			case jsonFormat:
+				text, tooltip = applyFormats(text, tooltip, map[string]any{"gpus": all})
+				formatting.FormatJSONOutput(text, tooltip, gpuClass(all...), -1, "", noTooltipFlag)
+			default:
+				fmt.Println(text)
+			}
+			return
+		}
+
+		metrics, err := gpuMetrics()
 		if err != nil {
 			switch formatFlag {
 			case jsonFormat:
@@ -168,11 +484,17 @@ var gpuAllCmd = &cobra.Command{
 			return
 		}
 
+		if metrics.Suspended {
+			printSuspended()
+			return
+		}
+
 		switch formatFlag {
 		case jsonFormat:
 			text := formatGPUAllMetrics(metrics)
 			_, tooltip := formatWithSymbols(metrics)
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), float64(metrics.Utilization), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatGPUAllMetrics(metrics))
 		}
@@ -187,8 +509,12 @@ var gpuPowerCmd = &cobra.Command{
 			return
 		}
 
-		power, err := gpu.GetPower()
+		power, err := gpuValue(gpu.GetPower, func(m *gpu.Metrics) float64 { return m.Power })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -201,18 +527,24 @@ var gpuPowerCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
 			}
 			
-			text := formatPower(power)
+			text := formatPower(power, metrics.PowerCap)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), percentOf(power, metrics.PowerCap), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
-			fmt.Println(formatPower(power))
+			// The power cap only sizes the reading in fixed-width mode
+			var powerCap float64
+			if formatting.FixedWidth() {
+				powerCap, _ = gpuField("power_cap", gpu.GetPowerCap, func(m *gpu.Metrics) float64 { return m.PowerCap })
+			}
+			fmt.Println(formatPower(power, powerCap))
 		}
 	},
 }
@@ -225,8 +557,12 @@ var gpuTempCmd = &cobra.Command{
 			return
 		}
 
-		temp, err := gpu.GetTemperature()
+		temp, err := gpuValue(gpu.GetTemperature, func(m *gpu.Metrics) int { return m.Temperature })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -239,7 +575,7 @@ var gpuTempCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -248,7 +584,8 @@ var gpuTempCmd = &cobra.Command{
 			text := formatTemp(temp)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), percentOf(float64(temp), gpuTempMax("temp", defaultGPUTempMax)), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatTemp(temp))
 		}
@@ -263,8 +600,12 @@ var gpuFreqCmd = &cobra.Command{
 			return
 		}
 
-		freq, err := gpu.GetFrequency()
+		freq, err := gpuValue(gpu.GetFrequency, func(m *gpu.Metrics) float64 { return m.Frequency })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -277,7 +618,7 @@ var gpuFreqCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -286,7 +627,8 @@ var gpuFreqCmd = &cobra.Command{
 			text := formatFreq(freq)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), -1, formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatFreq(freq))
 		}
@@ -296,13 +638,29 @@ var gpuFreqCmd = &cobra.Command{
 var gpuUtilCmd = &cobra.Command{
 	Use:   "util",
 	Short: "Get GPU utilization",
+	PreRunE: func(_ *cobra.Command, _ []string) error {
+		if utilEngineFlag == "" {
+			return nil
+		}
+		return gpu.ValidateEngine(utilEngineFlag)
+	},
 	Run: func(_ *cobra.Command, _ []string) {
 		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
 			return
 		}
 
-		util, err := gpu.GetUtilization()
+		var util int
+		var err error
+		if utilEngineFlag != "" {
+			util, err = engineUtil(utilEngineFlag)
+		} else {
+			util, err = gpuValue(gpu.GetUtilization, func(m *gpu.Metrics) int { return m.Utilization })
+		}
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -315,7 +673,7 @@ var gpuUtilCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -323,8 +681,14 @@ var gpuUtilCmd = &cobra.Command{
 			
 			text := formatUtil(util)
 			_, tooltip := formatWithSymbols(metrics)
+			if utilTopFlag > 0 {
+				if processes, err := gpu.GetProcesses(utilTopFlag); err == nil {
+					tooltip += "\n\n" + formatProcessList(processes)
+				}
+			}
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), float64(util), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatUtil(util))
 		}
@@ -332,15 +696,64 @@ var gpuUtilCmd = &cobra.Command{
 }
 
 var gpuMemoryCmd = &cobra.Command{
-	Use:   "memory",
-	Short: "Get VRAM usage percentage",
+	Use:     "memory",
+	Short:   "Get VRAM usage",
+	PreRunE: validateMemoryUnit,
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		vram, err := gpuField("vram", gpu.GetVRAM, func(m *gpu.Metrics) gpu.MemoryInfo { return m.VRAM })
+		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			// Get all metrics for tooltip
+			metrics, err := gpuMetrics()
+			if err != nil {
+				fmt.Println("{}")
+				return
+			}
+			
+			text := formatMemoryPool(vram)
+			_, tooltip := formatWithSymbols(metrics)
+			
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), vram.Percent(), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
+		default:
+			fmt.Println(formatMemoryPool(vram))
+		}
+	},
+}
+
+var gpuGTTCmd = &cobra.Command{
+	Use:     "gtt",
+	Short:   "Get GTT (GPU-mapped system memory) usage",
+	PreRunE: validateMemoryUnit,
 	Run: func(_ *cobra.Command, _ []string) {
 		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
 			return
 		}
 
-		memory, err := gpu.GetMemoryUsage()
+		gtt, err := gpuField("gtt", gpu.GetGTT, func(m *gpu.Metrics) gpu.MemoryInfo { return m.GTT })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -353,18 +766,19 @@ var gpuMemoryCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
 			}
 			
-			text := formatMemory(memory)
+			text := formatMemoryPool(gtt)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), gtt.Percent(), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
-			fmt.Println(formatMemory(memory))
+			fmt.Println(formatMemoryPool(gtt))
 		}
 	},
 }
@@ -377,8 +791,12 @@ var gpuFanCmd = &cobra.Command{
 			return
 		}
 
-		fan, err := gpu.GetFanSpeed()
+		fan, err := gpuField("fan_speed", gpu.GetFanSpeed, func(m *gpu.Metrics) int { return m.FanSpeed })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -391,7 +809,7 @@ var gpuFanCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -400,7 +818,8 @@ var gpuFanCmd = &cobra.Command{
 			text := formatFan(fan)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), -1, formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatFan(fan))
 		}
@@ -415,8 +834,12 @@ var gpuVoltageCmd = &cobra.Command{
 			return
 		}
 
-		voltage, err := gpu.GetVoltage()
+		voltage, err := gpuField("voltage", gpu.GetVoltage, func(m *gpu.Metrics) float64 { return m.Voltage })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -429,7 +852,7 @@ var gpuVoltageCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -438,7 +861,8 @@ var gpuVoltageCmd = &cobra.Command{
 			text := formatVoltage(voltage)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), -1, formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatVoltage(voltage))
 		}
@@ -453,8 +877,12 @@ var gpuJunctionCmd = &cobra.Command{
 			return
 		}
 
-		junctionTemp, err := gpu.GetJunctionTemp()
+		junctionTemp, err := gpuField("junction_temp", gpu.GetJunctionTemp, func(m *gpu.Metrics) int { return m.JunctionTemp })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -467,7 +895,7 @@ var gpuJunctionCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -476,7 +904,8 @@ var gpuJunctionCmd = &cobra.Command{
 			text := formatJunctionTemp(junctionTemp)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), percentOf(float64(junctionTemp), gpuTempMax("junction", defaultJunctionTempMax)), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatJunctionTemp(junctionTemp))
 		}
@@ -491,8 +920,12 @@ var gpuMemTempCmd = &cobra.Command{
 			return
 		}
 
-		memTemp, err := gpu.GetMemoryTemp()
+		memTemp, err := gpuField("memory_temp", gpu.GetMemoryTemp, func(m *gpu.Metrics) int { return m.MemoryTemp })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -505,7 +938,7 @@ var gpuMemTempCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -514,7 +947,8 @@ var gpuMemTempCmd = &cobra.Command{
 			text := formatMemoryTemp(memTemp)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), percentOf(float64(memTemp), gpuTempMax("memtemp", defaultMemoryTempMax)), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatMemoryTemp(memTemp))
 		}
@@ -529,8 +963,12 @@ var gpuPowerCapCmd = &cobra.Command{
 			return
 		}
 
-		powerCap, err := gpu.GetPowerCap()
+		powerCap, err := gpuField("power_cap", gpu.GetPowerCap, func(m *gpu.Metrics) float64 { return m.PowerCap })
 		if err != nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
 			switch formatFlag {
 			case jsonFormat:
 				fmt.Println("{}")
@@ -543,7 +981,7 @@ var gpuPowerCapCmd = &cobra.Command{
 		switch formatFlag {
 		case jsonFormat:
 			// Get all metrics for tooltip
-			metrics, err := gpu.GetAllMetrics()
+			metrics, err := gpuMetrics()
 			if err != nil {
 				fmt.Println("{}")
 				return
@@ -552,14 +990,97 @@ var gpuPowerCapCmd = &cobra.Command{
 			text := formatPowerCap(powerCap)
 			_, tooltip := formatWithSymbols(metrics)
 			
-			formatting.FormatJSONOutput(text, tooltip, "custom-gpu", noTooltipFlag)
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), percentOf(metrics.Power, powerCap), formatThrottleAlt(metrics.Throttle), noTooltipFlag)
 		default:
 			fmt.Println(formatPowerCap(powerCap))
 		}
 	},
 }
 
+var gpuThrottleCmd = &cobra.Command{
+	Use:   "throttle",
+	Short: "Get GPU throttle reasons",
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		reasons, err := gpuValue(gpu.GetThrottleReasons, func(m *gpu.Metrics) []string { return m.Throttle })
+		if err != nil || reasons == nil {
+			if errors.Is(err, gpu.ErrSuspended) {
+				printSuspended()
+				return
+			}
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			// Get all metrics for tooltip
+			metrics, err := gpuMetrics()
+			if err != nil {
+				fmt.Println("{}")
+				return
+			}
+			
+			text := formatThrottle(reasons)
+			_, tooltip := formatWithSymbols(metrics)
+			
+			text, tooltip = applyFormats(text, tooltip, metrics)
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(metrics), -1, formatThrottleAlt(reasons), noTooltipFlag)
+		default:
+			fmt.Println(formatThrottle(reasons))
+		}
+	},
+}
+
+var gpuTopCmd = &cobra.Command{
+	Use:   "top",
+	Short: "Get the processes using the GPU the most",
+	Run: func(_ *cobra.Command, _ []string) {
+		if !formatting.ValidateNoTooltipFlag(noTooltipFlag, formatFlag) {
+			return
+		}
+
+		processes, err := gpu.GetProcesses(topCountFlag)
+		if err != nil {
+			switch formatFlag {
+			case jsonFormat:
+				fmt.Println("{}")
+			default:
+				return
+			}
+			return
+		}
+
+		switch formatFlag {
+		case jsonFormat:
+			text := formatTopProcess(processes)
+			tooltip := formatProcessList(processes)
+			text, tooltip = applyFormats(text, tooltip, map[string]any{"processes": processes})
+			formatting.FormatJSONOutput(text, tooltip, gpuClass(), -1, "", noTooltipFlag)
+		default:
+			fmt.Println(formatTopProcess(processes))
+		}
+	},
+}
+
 func init() {
+	gpuCmd.PersistentFlags().StringVar(&gpuSelectFlag, "gpu", "", "GPU to monitor: index, card name, PCI address, igpu or dgpu (default: first discrete GPU)")
+	gpuAllCmd.Flags().BoolVar(&allGPUsFlag, "all-gpus", false, "List every GPU instead of the selected one")
+	gpuTopCmd.Flags().IntVar(&topCountFlag, "count", 5, "Number of processes to list")
+	gpuUtilCmd.Flags().IntVar(&utilTopFlag, "top", 0, "Add the top N GPU processes to the tooltip")
+	gpuMemoryCmd.Flags().StringVar(&memoryUnitFlag, "memory-unit", "percent", "Memory display: percent, bytes or both")
+	gpuGTTCmd.Flags().StringVar(&memoryUnitFlag, "memory-unit", "percent", "Memory display: percent, bytes or both")
+	gpuUtilCmd.Flags().StringVar(&utilEngineFlag, "engine", "", "Report a single engine: gfx, compute, video, encode, decode or dma")
+
 	gpuCmd.AddCommand(gpuAllCmd)
 	gpuCmd.AddCommand(gpuPowerCmd)
 	gpuCmd.AddCommand(gpuTempCmd)
@@ -571,4 +1092,7 @@ func init() {
 	gpuCmd.AddCommand(gpuJunctionCmd)
 	gpuCmd.AddCommand(gpuMemTempCmd)
 	gpuCmd.AddCommand(gpuPowerCapCmd)
+	gpuCmd.AddCommand(gpuThrottleCmd)
+	gpuCmd.AddCommand(gpuTopCmd)
+	gpuCmd.AddCommand(gpuGTTCmd)
 }
\ No newline at end of file
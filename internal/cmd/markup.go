@@ -0,0 +1,50 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"errors"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+	"github.com/bnema/waybar-amd-module/internal/formatting"
+	"github.com/spf13/cobra"
+)
+
+var (
+	markupFlag    bool
+	gradientFlag  []string
+	tempRangeFlag []float64
+)
+
+// loadMarkup enables Pango markup from --markup or the config file, with
+// the gradient and temperature range from flags over the config file
+func loadMarkup(cmd *cobra.Command, cfg *config.Config) error {
+	if !markupFlag && !cfg.Markup.Enabled {
+		return nil
+	}
+
+	gradient := formatting.DefaultGradient
+	if len(cfg.Markup.Gradient) > 0 {
+		gradient = cfg.Markup.Gradient
+	}
+	if cmd.Flags().Changed("gradient") {
+		gradient = gradientFlag
+	}
+
+	tempRange := formatting.DefaultTempRange
+	if cfg.Markup.TempRange != nil {
+		tempRange = *cfg.Markup.TempRange
+	}
+	if cmd.Flags().Changed("temp-range") {
+		if len(tempRangeFlag) != 2 {
+			return errors.New("--temp-range expects two values, e.g. 40,95")
+		}
+		tempRange = [2]float64{tempRangeFlag[0], tempRangeFlag[1]}
+	}
+
+	m, err := formatting.NewMarkup(gradient, tempRange)
+	if err != nil {
+		return err
+	}
+	formatting.SetMarkup(m)
+	return nil
+}
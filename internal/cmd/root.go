@@ -2,8 +2,11 @@
 package cmd
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/bnema/waybar-amd-module/internal/discovery"
+	"github.com/bnema/waybar-amd-module/internal/formatting"
 )
 
 var (
@@ -11,6 +14,8 @@ var (
 	nerdFontFlag    bool
 	noTooltipFlag   bool
 	withPstateFlag  bool
+	watchFlag       bool
+	intervalFlag    time.Duration
 	
 	pathCache *discovery.PathCache
 )
@@ -19,17 +24,37 @@ var rootCmd = &cobra.Command{
 	Use:   "waybar-amd-module",
 	Short: "AMD GPU and CPU metrics for Waybar",
 	Long:  "Monitor AMD GPU and CPU metrics with automatic hardware discovery and smart caching",
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		return loadConfig(cmd)
+	},
 }
 
 func init() {
+	// gpu selects its device in its own PersistentPreRunE, run both
+	cobra.EnableTraverseRunHooks = true
+	
 	rootCmd.PersistentFlags().StringVar(&formatFlag, "format", "json", "Output format (json/text)")
 	rootCmd.PersistentFlags().BoolVar(&nerdFontFlag, "nerd-font", false, "Use nerd font symbols in output")
 	rootCmd.PersistentFlags().BoolVar(&noTooltipFlag, "no-tooltip", false, "Remove tooltip field from JSON output")
 	rootCmd.PersistentFlags().BoolVar(&withPstateFlag, "with-pstate", false, "Include AMD pstate information in CPU metrics")
+	rootCmd.PersistentFlags().BoolVar(&watchFlag, "watch", false, "Keep running and print one line per interval (Waybar continuous mode)")
+	rootCmd.PersistentFlags().DurationVar(&intervalFlag, "interval", 0, "Interval between lines in watch mode, implies --watch (default 2s)")
+	rootCmd.PersistentFlags().StringArrayVar(&warnFlag, "warn", nil, "Warning threshold as metric=value, adds the warning class (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&critFlag, "crit", nil, "Critical threshold as metric=value, adds the critical class (repeatable)")
+	rootCmd.PersistentFlags().StringArrayVar(&hysteresisFlag, "hysteresis", nil, "How far a metric must drop below its threshold to clear its class, e.g. temp=3 (default 3% of the threshold)")
+	rootCmd.PersistentFlags().StringVar(&textFormatFlag, "text-format", "", "Template for the text: Go text/template ({{.Power}}) or placeholders ({power})")
+	rootCmd.PersistentFlags().StringVar(&tooltipFormatFlag, "tooltip-format", "", "Template for the tooltip, same syntax as --text-format")
+	rootCmd.PersistentFlags().BoolVar(&markupFlag, "markup", false, "Emit Pango markup: values colored on a gradient, aligned tooltip")
+	rootCmd.PersistentFlags().StringSliceVar(&gradientFlag, "gradient", formatting.DefaultGradient, "Markup gradient colors from low to high values")
+	rootCmd.PersistentFlags().Float64SliceVar(&tempRangeFlag, "temp-range", formatting.DefaultTempRange[:], "Temperature range in °C spanned by the markup gradient")
+	rootCmd.PersistentFlags().BoolVar(&fixedWidthFlag, "fixed-width", false, "Pad numbers to their widest value so the module keeps its width")
+	rootCmd.PersistentFlags().StringVar(&padCharFlag, "pad-char", formatting.FigureSpace, "Pad character of --fixed-width, a figure space by default")
+	rootCmd.PersistentFlags().BoolVar(&noDaemonFlag, "no-daemon", false, "Always read sysfs directly instead of querying a running daemon")
 	
 	rootCmd.AddCommand(gpuCmd)
 	rootCmd.AddCommand(cpuCmd)
 	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(daemonCmd)
 }
 
 // SetPathCache sets the path cache for use by commands
@@ -39,5 +64,6 @@ func SetPathCache(cache *discovery.PathCache) {
 
 // Execute runs the root command
 func Execute() error {
+	enableWatchMode(rootCmd)
 	return rootCmd.Execute()
 }
\ No newline at end of file
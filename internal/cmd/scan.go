@@ -4,6 +4,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 )
@@ -23,6 +24,25 @@ var scanCmd = &cobra.Command{
 		}
 
 		fmt.Println("Hardware scan completed successfully!")
+		for i, gpu := range pathCache.GPU {
+			kind := "discrete"
+			if gpu.Integrated {
+				kind = "integrated"
+			}
+			// GPUs found through the PCI driver fallback have no card node
+			card := "-"
+			if gpu.Card != "" {
+				card = filepath.Base(gpu.Card)
+			}
+			fmt.Printf("GPU %d: %s [%s, %s, %s]\n", i, gpu.Name, card, gpu.PCI, kind)
+		}
+		if pathCache.CPU != nil {
+			ccds := make(map[int]bool)
+			for _, core := range pathCache.CPU.Topology {
+				ccds[core.CCD] = true
+			}
+			fmt.Printf("CPU: %d cores, %d cpufreq policies, %d CCDs\n", pathCache.CPU.CoreCount, len(pathCache.CPU.Policies), len(ccds))
+		}
 		fmt.Printf("Cache updated: %s\n", pathCache.GetCacheFile())
 		return nil
 	},
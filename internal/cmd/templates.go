@@ -0,0 +1,79 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+	"github.com/bnema/waybar-amd-module/internal/formatting"
+	"github.com/spf13/cobra"
+)
+
+var (
+	textFormatFlag    string
+	tooltipFormatFlag string
+
+	textTemplate    *formatting.Template
+	tooltipTemplate *formatting.Template
+)
+
+// formatKey returns the config key of a command, e.g. "gpu.all"
+func formatKey(cmd *cobra.Command) string {
+	return strings.Join(strings.Fields(cmd.CommandPath())[1:], ".")
+}
+
+// loadFormats parses --text-format and --tooltip-format, falling back to
+// the formats of the command in the config file. Templates only apply to
+// JSON output, so the flags are rejected with --format text.
+func loadFormats(cmd *cobra.Command, cfg *config.Config) error {
+	if formatFlag != jsonFormat && (textFormatFlag != "" || tooltipFormatFlag != "") {
+		return errors.New("--text-format and --tooltip-format require --format json")
+	}
+	if formatFlag != jsonFormat {
+		return nil
+	}
+
+	format := cfg.Formats[formatKey(cmd)]
+	if textFormatFlag != "" {
+		format.Text = textFormatFlag
+	}
+	if tooltipFormatFlag != "" {
+		format.Tooltip = tooltipFormatFlag
+	}
+
+	var err error
+	if format.Text != "" {
+		if textTemplate, err = formatting.ParseTemplate(format.Text); err != nil {
+			return errors.New("text format: " + err.Error())
+		}
+	}
+	if format.Tooltip != "" {
+		if tooltipTemplate, err = formatting.ParseTemplate(format.Tooltip); err != nil {
+			return errors.New("tooltip format: " + err.Error())
+		}
+	}
+	return nil
+}
+
+// applyFormats renders the user templates over data, keeping the built-in
+// text or tooltip when no template is set or rendering fails. Lists are
+// passed wrapped in a map, e.g. {"processes": ...}, so placeholders resolve.
+func applyFormats(text, tooltip string, data any) (string, string) {
+	if textTemplate != nil {
+		if rendered, err := textTemplate.Render(data); err == nil {
+			text = rendered
+		} else {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	if tooltipTemplate != nil {
+		if rendered, err := tooltipTemplate.Render(data); err == nil {
+			tooltip = rendered
+		} else {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	return text, tooltip
+}
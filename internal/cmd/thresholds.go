@@ -0,0 +1,130 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"slices"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+	"github.com/bnema/waybar-amd-module/internal/cpu"
+	"github.com/bnema/waybar-amd-module/internal/formatting"
+	"github.com/bnema/waybar-amd-module/internal/gpu"
+	"github.com/spf13/cobra"
+)
+
+var (
+	warnFlag       []string
+	critFlag       []string
+	hysteresisFlag []string
+
+	thresholds map[string]config.Threshold
+)
+
+// Metrics accepted by --warn and --crit for each device
+var (
+	cpuThresholdMetrics = []string{"usage", "temp", "freq", "memory", "load", "iowait", "power"}
+	gpuThresholdMetrics = []string{"usage", "temp", "junction", "memtemp", "freq", "memory", "power", "fan"}
+)
+
+// commandDevice returns the top-level command (cpu, gpu, scan, ...) of cmd
+func commandDevice(cmd *cobra.Command) string {
+	device := cmd
+	for device.HasParent() && device.Parent().HasParent() {
+		device = device.Parent()
+	}
+	return device.Name()
+}
+
+// loadConfig reads the config file for the cpu and gpu commands and applies
+// the thresholds, formats, markup and fixed-width settings of the command
+func loadConfig(cmd *cobra.Command) error {
+	device := commandDevice(cmd)
+	if device != "cpu" && device != "gpu" {
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	if err := loadThresholds(device, cfg); err != nil {
+		return err
+	}
+	if err := loadFormats(cmd, cfg); err != nil {
+		return err
+	}
+	if err := loadMarkup(cmd, cfg); err != nil {
+		return err
+	}
+	return loadFixedWidth(cmd, cfg)
+}
+
+// loadThresholds merges the config file thresholds of the device with
+// --warn, --crit and --hysteresis
+func loadThresholds(device string, cfg *config.Config) error {
+	metrics := cpuThresholdMetrics
+	if device == "gpu" {
+		metrics = gpuThresholdMetrics
+	}
+
+	var err error
+	thresholds, err = formatting.ParseThresholds(cfg.Thresholds[device], warnFlag, critFlag, hysteresisFlag, metrics)
+	return err
+}
+
+// classThrottling is the state class added while the CPU or GPU is throttled
+const classThrottling = "throttling"
+
+// cpuClass returns the Waybar classes for CPU output: the base class plus
+// the threshold states of the metrics, if any
+func cpuClass(base string, metrics *cpu.Metrics) []string {
+	if metrics == nil {
+		return []string{base}
+	}
+
+	values := map[string]float64{
+		"usage":  metrics.Usage,
+		"temp":   float64(metrics.Temperature),
+		"freq":   metrics.Frequency,
+		"memory": metrics.MemoryUsage,
+		"load":   metrics.LoadAvg,
+		"iowait": metrics.IOWait,
+		"power":  metrics.PackagePower,
+	}
+	return append([]string{base}, formatting.ThresholdClasses("cpu", values, thresholds)...)
+}
+
+// gpuClass returns the Waybar classes for GPU output, flagging active
+// throttling and the threshold states of every given GPU
+func gpuClass(all ...*gpu.Metrics) []string {
+	var states []string
+	for _, metrics := range all {
+		if len(metrics.Throttle) > 0 && !slices.Contains(states, classThrottling) {
+			states = append(states, classThrottling)
+		}
+		if metrics.Suspended {
+			continue
+		}
+
+		values := map[string]float64{
+			"usage":    float64(metrics.Utilization),
+			"temp":     float64(metrics.Temperature),
+			"junction": float64(metrics.JunctionTemp),
+			"memtemp":  float64(metrics.MemoryTemp),
+			"freq":     metrics.Frequency,
+			"memory":   metrics.MemoryUsage,
+			"power":    metrics.Power,
+			"fan":      float64(metrics.FanSpeed),
+		}
+		for _, class := range formatting.ThresholdClasses("gpu:"+metrics.PCI, values, thresholds) {
+			if !slices.Contains(states, class) {
+				states = append(states, class)
+			}
+		}
+	}
+
+	// Several GPUs may disagree, critical wins over warning
+	if slices.Contains(states, formatting.ClassCritical) {
+		states = slices.DeleteFunc(states, func(class string) bool { return class == formatting.ClassWarning })
+	}
+	return append([]string{"custom-gpu"}, states...)
+}
@@ -0,0 +1,53 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// watchEnabled reports whether commands should keep running and emit one line per tick
+func watchEnabled() bool {
+	return watchFlag || intervalFlag > 0
+}
+
+// watchInterval returns the delay between two emitted lines in watch mode
+func watchInterval() time.Duration {
+	if intervalFlag > 0 {
+		return intervalFlag
+	}
+	return defaultWatchInterval
+}
+
+// enableWatchMode wraps the Run function of every metric command so that it
+// repeats on each tick when --watch or --interval is set. Commands using RunE
+// (scan, daemon, ...) are one-shot and left untouched.
+func enableWatchMode(parent *cobra.Command) {
+	for _, child := range parent.Commands() {
+		enableWatchMode(child)
+	}
+
+	if parent.Run == nil {
+		return
+	}
+
+	run := parent.Run
+	parent.Run = func(cmd *cobra.Command, args []string) {
+		if !watchEnabled() {
+			run(cmd, args)
+			return
+		}
+
+		ticker := time.NewTicker(watchInterval())
+		defer ticker.Stop()
+
+		for {
+			resetDaemonSnapshot()
+			run(cmd, args)
+			<-ticker.C
+		}
+	}
+}
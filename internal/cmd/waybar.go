@@ -0,0 +1,80 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"github.com/bnema/waybar-amd-module/internal/cpu"
+)
+
+// Temperatures mapped to 100% when no critical threshold is configured
+const (
+	defaultCPUTempMax      = 95
+	defaultGPUTempMax      = 100
+	defaultJunctionTempMax = 110
+	defaultMemoryTempMax   = 105
+)
+
+// percentOf scales value to the 0-100 Waybar percentage, or -1 without a maximum
+func percentOf(value, maximum float64) float64 {
+	if maximum <= 0 {
+		return -1
+	}
+	return min(max(value/maximum*100, 0), 100)
+}
+
+// cpuTempMax is the CPU temperature shown as 100%: the critical threshold,
+// else the thermal limit of the CPU
+func cpuTempMax(metrics *cpu.Metrics) float64 {
+	if limit := thresholds["temp"].Critical; limit > 0 {
+		return limit
+	}
+	if metrics.Throttle.ThermalLimit > 0 {
+		return float64(metrics.Throttle.ThermalLimit)
+	}
+	return defaultCPUTempMax
+}
+
+// gpuTempMax is the GPU temperature shown as 100%: the critical threshold of
+// the metric, else a typical limit for the sensor
+func gpuTempMax(metric string, fallback float64) float64 {
+	if limit := thresholds[metric].Critical; limit > 0 {
+		return limit
+	}
+	return fallback
+}
+
+// cpuAlt returns the alt keyword of CPU output: the energy performance
+// preference when amd-pstate exposes one, else the governor
+func cpuAlt(metrics *cpu.Metrics) string {
+	if metrics.EnergyPerfPreference != "" && metrics.EnergyPerfPreference != "not_available" {
+		return metrics.EnergyPerfPreference
+	}
+	return metrics.Governor
+}
+
+// formatBoostAlt returns the alt keyword of the boost state
+func formatBoostAlt(boost bool) string {
+	if boost {
+		return "on"
+	}
+	return "off"
+}
+
+// formatPowerAlt returns the alt keyword of the battery power direction
+func formatPowerAlt(power float64) string {
+	switch {
+	case power > 0:
+		return "charging"
+	case power < 0:
+		return "discharging"
+	default:
+		return "idle"
+	}
+}
+
+// formatThrottleAlt returns the main throttle reason, or none
+func formatThrottleAlt(reasons []string) string {
+	if len(reasons) == 0 {
+		return "none"
+	}
+	return reasons[0]
+}
@@ -0,0 +1,69 @@
+// Package cmd provides CLI commands for monitoring AMD hardware metrics
+package cmd
+
+import (
+	"errors"
+	"runtime"
+	"unicode/utf8"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+	"github.com/bnema/waybar-amd-module/internal/formatting"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fixedWidthFlag bool
+	padCharFlag    string
+)
+
+// Widest plausible values of each metric, numbers are padded to their width
+const (
+	widestPercent      = 100
+	widestTemp         = 100
+	widestFreq         = 9.9
+	widestVoltage      = 9.99
+	widestFan          = 9999
+	widestGPUPowerCap  = 999.9
+	widestPackagePower = 999.9
+	widestBatteryPower = 100
+)
+
+// loadFixedWidth enables number padding from --fixed-width or the config
+// file, with the pad character from the flag over the config file
+func loadFixedWidth(cmd *cobra.Command, cfg *config.Config) error {
+	if !fixedWidthFlag && !cfg.FixedWidth {
+		return nil
+	}
+
+	pad := formatting.FigureSpace
+	if cfg.PadChar != "" {
+		pad = cfg.PadChar
+	}
+	if cmd.Flags().Changed("pad-char") {
+		pad = padCharFlag
+	}
+	if utf8.RuneCountInString(pad) != 1 {
+		return errors.New("--pad-char expects a single character")
+	}
+
+	formatting.SetFixedWidth(pad)
+	return nil
+}
+
+// widestGPUPower is the widest GPU power reading: the power cap when known
+func widestGPUPower(powerCap float64) float64 {
+	if powerCap > 0 {
+		return powerCap
+	}
+	return widestGPUPowerCap
+}
+
+// widestCPU is the highest logical CPU count, bounding load averages and CPU ids
+func widestCPU() float64 {
+	return float64(runtime.NumCPU())
+}
+
+// widestCPUID is the highest logical CPU id
+func widestCPUID() float64 {
+	return widestCPU() - 1
+}
@@ -0,0 +1,78 @@
+// Package config loads the optional user configuration file
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// Threshold holds the warning and critical levels of one metric. A level of
+// 0 is disabled.
+type Threshold struct {
+	Warning  float64 `json:"warning"`
+	Critical float64 `json:"critical"`
+	// Hysteresis is how far the value must fall below a level before it is
+	// cleared, in the unit of the metric. Unset uses a fraction of the level.
+	Hysteresis *float64 `json:"hysteresis,omitempty"`
+}
+
+// Format is a user-defined text and tooltip layout for one command
+type Format struct {
+	Text    string `json:"text"`
+	Tooltip string `json:"tooltip"`
+}
+
+// Markup configures the Pango markup mode
+type Markup struct {
+	Enabled bool `json:"enabled"`
+	// Gradient lists #rrggbb colors from low to high values
+	Gradient []string `json:"gradient"`
+	// TempRange is the temperature span in °C mapped onto the gradient
+	TempRange *[2]float64 `json:"temp_range"`
+}
+
+// Config is the content of config.json. Command-line flags override it.
+type Config struct {
+	// Thresholds maps a device (cpu or gpu) to per-metric thresholds
+	Thresholds map[string]map[string]Threshold `json:"thresholds"`
+	// Formats maps a command such as "gpu.all" to its layout
+	Formats map[string]Format `json:"formats"`
+	Markup  Markup            `json:"markup"`
+	// FixedWidth pads numbers to their widest value
+	FixedWidth bool `json:"fixed_width"`
+	// PadChar replaces the figure space used by FixedWidth
+	PadChar string `json:"pad_char"`
+}
+
+// Path returns the config file path, under $XDG_CONFIG_HOME when set
+func Path() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", errors.New("failed to locate config directory: " + err.Error())
+	}
+	return filepath.Join(configDir, "waybar-amd-module", "config.json"), nil
+}
+
+// Load reads the config file. A missing file yields an empty config.
+func Load() (*Config, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - path is the user's own config file
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, errors.New("failed to read config: " + err.Error())
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.New("failed to parse " + path + ": " + err.Error())
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,204 @@
+// Package cpu provides core class detection for hybrid parts (Zen 5 + Zen 5c)
+package cpu
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoreClass groups the logical CPUs of one core type with their current load
+type CoreClass struct {
+	Name        string  `json:"name"`
+	CPUs        []int   `json:"cpus"`
+	MaxFreq     float64 `json:"max_freq"`    // GHz, highest cpuinfo_max_freq of the class
+	Usage       float64 `json:"usage"`       // average over the class
+	Frequency   float64 `json:"frequency"`   // GHz, average over the class
+	Temperature int     `json:"temperature"` // hottest CCD of the class, 0 when unknown
+}
+
+// classGap is the relative drop in maximum frequency that starts a new core
+// class. Zen 5c cores top out around 35% below Zen 5, while prefcore ranking
+// only spreads cores of one class by a few percent.
+const classGap = 0.15
+
+// coreClasses caches the class membership, which cannot change at runtime
+var coreClasses []CoreClass
+
+// GetCoreClasses returns the core classes with their current usage, frequency
+// and temperature. Homogeneous CPUs have a single class.
+func GetCoreClasses() ([]CoreClass, error) {
+	classes, err := classifyCores()
+	if err != nil {
+		return nil, err
+	}
+
+	cores, err := GetCoreStats()
+	if err != nil {
+		return nil, err
+	}
+
+	// Temperatures are optional, many mobile parts only report Tctl
+	sensors, _ := GetTempSensors()
+
+	return CoreClassLoad(classes, cores, sensors), nil
+}
+
+// CoreClassLoad fills the usage, frequency and temperature of each class
+func CoreClassLoad(classes []CoreClass, cores []CoreStat, sensors TempSensors) []CoreClass {
+	load := make(map[int]CoreStat, len(cores))
+	for _, core := range cores {
+		load[core.CPU] = core
+	}
+	ccds := coreCCDs()
+
+	result := make([]CoreClass, 0, len(classes))
+	for _, class := range classes {
+		var usage, freq float64
+		var count int
+		for _, id := range class.CPUs {
+			if core, ok := load[id]; ok {
+				usage += core.Usage
+				freq += core.Frequency
+				count++
+			}
+			class.Temperature = max(class.Temperature, ccdTemperature(sensors, ccds[id]))
+		}
+		if count > 0 {
+			class.Usage = usage / float64(count)
+			class.Frequency = freq / float64(count)
+		}
+		result = append(result, class)
+	}
+
+	return result
+}
+
+// classifyCores splits the logical CPUs into classes by maximum frequency,
+// falling back to amd_pstate_highest_perf when cpuinfo_max_freq is missing
+func classifyCores() ([]CoreClass, error) {
+	if coreClasses != nil {
+		return coreClasses, nil
+	}
+
+	limits, err := readCoreFreqFile("cpuinfo_max_freq")
+	if err != nil {
+		limits, err = readCoreHighestPerf()
+		if err != nil {
+			return nil, errors.New("no per-core frequency limits available")
+		}
+	}
+
+	ids := make([]int, 0, len(limits))
+	for id := range limits {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if limits[ids[i]] != limits[ids[j]] {
+			return limits[ids[i]] > limits[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	var classes []CoreClass
+	var floor float64
+	for _, id := range ids {
+		limit := limits[id]
+		if len(classes) == 0 || limit < floor*(1-classGap) {
+			classes = append(classes, CoreClass{MaxFreq: limit / 1000000})
+		}
+		floor = limit
+		class := &classes[len(classes)-1]
+		class.CPUs = append(class.CPUs, id)
+	}
+
+	generation := zenGeneration()
+	for i := range classes {
+		sort.Ints(classes[i].CPUs)
+		classes[i].Name = className(generation, i)
+	}
+
+	coreClasses = classes
+	return coreClasses, nil
+}
+
+// className names a class from the Zen generation: the fastest class keeps
+// the generation name, slower ones are the compact (c) variant
+func className(generation string, index int) string {
+	switch {
+	case generation == "" && index == 0:
+		return "Core"
+	case generation == "":
+		return "Compact core"
+	case index == 0:
+		return generation
+	default:
+		return generation + "c"
+	}
+}
+
+// readCoreHighestPerf reads amd_pstate_highest_perf of every policy, keyed by CPU
+func readCoreHighestPerf() (map[int]float64, error) {
+	if cpuPaths == nil || len(cpuPaths.Policies) == 0 {
+		return nil, errors.New("no cpufreq policies found")
+	}
+
+	perf := make(map[int]float64)
+	for _, policy := range cpuPaths.Policies {
+		value := readPolicyInt(policy.Path, "amd_pstate_highest_perf", 0)
+		if value <= 0 {
+			continue
+		}
+		for _, id := range policy.CPUs {
+			perf[id] = float64(value)
+		}
+	}
+
+	if len(perf) == 0 {
+		return nil, errors.New("amd_pstate_highest_perf not available")
+	}
+	return perf, nil
+}
+
+// zenGeneration maps the family and model of /proc/cpuinfo to a Zen
+// generation, or "" when unknown
+func zenGeneration() string {
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer func() { _ = file.Close() }()
+
+	family, model := -1, -1
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() && (family < 0 || model < 0) {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "cpu family":
+			family, _ = strconv.Atoi(strings.TrimSpace(value))
+		case "model":
+			model, _ = strconv.Atoi(strings.TrimSpace(value))
+		}
+	}
+
+	switch {
+	case family == 0x17 && model >= 0x30:
+		return "Zen2"
+	case family == 0x17:
+		return "Zen"
+	case family == 0x19 && (model >= 0x10 && model <= 0x1f || model >= 0x60 && model <= 0x7f || model >= 0xa0 && model <= 0xaf):
+		return "Zen4"
+	case family == 0x19:
+		return "Zen3"
+	case family == 0x1a:
+		return "Zen5"
+	default:
+		return ""
+	}
+}
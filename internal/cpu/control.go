@@ -0,0 +1,170 @@
+// Package cpu provides AMD P-State and boost control
+package cpu
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// PstateModes lists the amd_pstate driver modes accepted by SetPstateStatus
+var PstateModes = []string{"active", "passive", "guided"}
+
+// permissionError adds a privilege hint to permission errors
+func permissionError(path string, err error) error {
+	if errors.Is(err, os.ErrPermission) {
+		return errors.New("permission denied writing " + path +
+			": run the command as root (e.g. pkexec waybar-amd-module cpu set ...) or grant access with a polkit rule")
+	}
+	return errors.New("failed to write " + path + ": " + err.Error())
+}
+
+// writeSysfs writes a value to a sysfs control file
+func writeSysfs(path, value string) error {
+	if err := os.WriteFile(path, []byte(value), 0644); err != nil { // #nosec G306 - sysfs files keep their own mode
+		return permissionError(path, err)
+	}
+	return nil
+}
+
+// policyDirs lists every discovered cpufreq policy directory
+func policyDirs() ([]string, error) {
+	if cpuPaths == nil || len(cpuPaths.Policies) == 0 {
+		return nil, errors.New("no cpufreq policies found")
+	}
+
+	dirs := make([]string, 0, len(cpuPaths.Policies))
+	for _, policy := range cpuPaths.Policies {
+		dirs = append(dirs, policy.Path)
+	}
+	return dirs, nil
+}
+
+// GetAvailableEnergyPerfPreferences returns the EPP values the driver accepts
+func GetAvailableEnergyPerfPreferences() ([]string, error) {
+	dirs, err := policyDirs()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dirs[0], "energy_performance_available_preferences"))
+	if err != nil {
+		return nil, errors.New("energy performance preferences not available: " + err.Error())
+	}
+
+	preferences := strings.Fields(string(data))
+	if len(preferences) == 0 {
+		return nil, errors.New("energy performance preferences not available: empty list")
+	}
+	return preferences, nil
+}
+
+// SetEnergyPerfPreference writes energy_performance_preference on every
+// policy and returns the value written. "next" cycles to the preference
+// after the current one.
+func SetEnergyPerfPreference(value string) (string, error) {
+	available, err := GetAvailableEnergyPerfPreferences()
+	if err != nil {
+		return "", err
+	}
+
+	if value == "next" {
+		current, _ := GetEnergyPerfPreference()
+		if value, err = nextValue(available, current); err != nil {
+			return "", err
+		}
+	}
+	if !slices.Contains(available, value) {
+		return "", errors.New("invalid energy performance preference " + value + ", expected one of " + strings.Join(available, ", "))
+	}
+
+	dirs, err := policyDirs()
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs {
+		if err := writeSysfs(filepath.Join(dir, "energy_performance_preference"), value); err != nil {
+			return "", err
+		}
+	}
+
+	return value, nil
+}
+
+// SetPstateStatus switches the amd_pstate driver mode and returns the mode
+// written. "next" cycles to the mode after the current one.
+func SetPstateStatus(mode string) (string, error) {
+	if cpuPaths == nil || cpuPaths.AMDPstateBase == "" {
+		return "", errors.New("amd_pstate driver not available")
+	}
+
+	if mode == "next" {
+		current, _ := GetPstateStatus()
+		var err error
+		if mode, err = nextValue(PstateModes, current); err != nil {
+			return "", err
+		}
+	}
+	if !slices.Contains(PstateModes, mode) {
+		return "", errors.New("invalid amd_pstate mode " + mode + ", expected one of " + strings.Join(PstateModes, ", "))
+	}
+
+	if err := writeSysfs(filepath.Join(cpuPaths.AMDPstateBase, "status"), mode); err != nil {
+		return "", err
+	}
+
+	return mode, nil
+}
+
+// SetBoost enables or disables boost and returns the new state. The global
+// cpufreq/boost file is used when present, otherwise every policy's boost.
+func SetBoost(value string) (bool, error) {
+	var enabled bool
+	switch value {
+	case "on", "1":
+		enabled = true
+	case "off", "0":
+		enabled = false
+	case "toggle":
+		current, err := GetBoostEnabled()
+		if err != nil {
+			return false, err
+		}
+		enabled = !current
+	default:
+		return false, errors.New("invalid boost value " + value + ", expected on, off or toggle")
+	}
+
+	state := "0"
+	if enabled {
+		state = "1"
+	}
+
+	// Discovery may have found a per-policy boost file, only the global one covers every core
+	if cpuPaths != nil && cpuPaths.BoostPath != "" && filepath.Dir(cpuPaths.BoostPath) == filepath.Join(cpuPaths.CPUFreqBase, "cpufreq") {
+		return enabled, writeSysfs(cpuPaths.BoostPath, state)
+	}
+
+	dirs, err := policyDirs()
+	if err != nil {
+		return false, err
+	}
+	for _, dir := range dirs {
+		if err := writeSysfs(filepath.Join(dir, "boost"), state); err != nil {
+			return false, err
+		}
+	}
+
+	return enabled, nil
+}
+
+// nextValue returns the value following current, wrapping around
+func nextValue(values []string, current string) (string, error) {
+	if len(values) == 0 {
+		return "", errors.New("no values to cycle through")
+	}
+	index := slices.Index(values, current)
+	return values[(index+1)%len(values)], nil
+}
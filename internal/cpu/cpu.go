@@ -32,11 +32,34 @@ type Metrics struct {
 	EnergyPerfPreference  string  `json:"energy_perf_preference"`
 	HighestPerf           int     `json:"highest_perf"`
 	LowestNonlinearFreq   float64 `json:"lowest_nonlinear_freq"`
+	PackagePower          float64 `json:"package_power"`
+	CorePower             float64 `json:"core_power"`
+	CoreStats             []CoreStat `json:"core_stats,omitempty"`
+	Throttle              ThrottleStatus `json:"throttle"`
+	Policies              []PolicyPstate `json:"policies,omitempty"`
+	CoreClasses           []CoreClass `json:"core_classes,omitempty"`
+	CCDs                  []DomainStat `json:"ccds,omitempty"`
+	CCXs                  []DomainStat `json:"ccxs,omitempty"`
+	UsageBreakdown
+	TempSensors
+	Errors                map[string]string `json:"errors,omitempty"` // unreadable fields by JSON name
 }
 
+// Err returns why the field with the given JSON name could not be read, or
+// nil when it holds a real value
+func (m *Metrics) Err(field string) error {
+	if msg, failed := m.Errors[field]; failed {
+		return errors.New(msg)
+	}
+	return nil
+}
 
 
-var cpuPaths *discovery.CPUPaths
+
+var (
+	cpuPaths   *discovery.CPUPaths
+	powerPaths *discovery.PowerPaths
+)
 
 // Initialize sets up the CPU package with discovered paths
 func Initialize(cache *discovery.PathCache) error {
@@ -44,98 +67,179 @@ func Initialize(cache *discovery.PathCache) error {
 		return errors.New("no CPU paths found in cache")
 	}
 	cpuPaths = cache.CPU
+	powerPaths = cache.Power
 	return nil
 }
 
 type cpuStat struct {
-	user, nice, system, idle, iowait, irq, softirq uint64
+	user, nice, system, idle, iowait, irq, softirq, steal, guest, guestNice uint64
 }
 
+// cpuStatColumns is the number of columns of a cpu line in /proc/stat
+const cpuStatColumns = 10
+
+// parseCPUStat parses a cpu line of /proc/stat. Columns missing on older
+// kernels (steal, guest, guest_nice) read as 0.
 func parseCPUStat(line string) (cpuStat, error) {
 	fields := strings.Fields(line)
 	if len(fields) < 8 {
 		return cpuStat{}, errors.New("invalid cpu stat line")
 	}
 	
-	var stat cpuStat
-	var err error
-	
-	stat.user, err = strconv.ParseUint(fields[1], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.nice, err = strconv.ParseUint(fields[2], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.system, err = strconv.ParseUint(fields[3], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.idle, err = strconv.ParseUint(fields[4], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.iowait, err = strconv.ParseUint(fields[5], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.irq, err = strconv.ParseUint(fields[6], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
-	}
-	stat.softirq, err = strconv.ParseUint(fields[7], 10, 64)
-	if err != nil {
-		return cpuStat{}, err
+	values := make([]uint64, cpuStatColumns)
+	for i, field := range fields[1:min(len(fields), cpuStatColumns+1)] {
+		value, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return cpuStat{}, err
+		}
+		values[i] = value
 	}
 	
+	stat, _ := cpuStatFromValues(values)
 	return stat, nil
 }
 
+// values lists the columns in /proc/stat order
+func (s cpuStat) values() []uint64 {
+	return []uint64{s.user, s.nice, s.system, s.idle, s.iowait, s.irq, s.softirq, s.steal, s.guest, s.guestNice}
+}
+
+// cpuStatFromValues is the inverse of values
+func cpuStatFromValues(values []uint64) (cpuStat, bool) {
+	if len(values) != cpuStatColumns {
+		return cpuStat{}, false
+	}
+	return cpuStat{
+		user:      values[0],
+		nice:      values[1],
+		system:    values[2],
+		idle:      values[3],
+		iowait:    values[4],
+		irq:       values[5],
+		softirq:   values[6],
+		steal:     values[7],
+		guest:     values[8],
+		guestNice: values[9],
+	}, true
+}
+
+// total excludes guest and guest_nice, which the kernel already counts in
+// user and nice
 func (s cpuStat) total() uint64 {
-	return s.user + s.nice + s.system + s.idle + s.iowait + s.irq + s.softirq
+	return s.user + s.nice + s.system + s.idle + s.iowait + s.irq + s.softirq + s.steal
 }
 
+// active counts steal as busy: the time was taken from this VM by the hypervisor
 func (s cpuStat) active() uint64 {
-	return s.user + s.nice + s.system + s.irq + s.softirq
+	return s.user + s.nice + s.system + s.irq + s.softirq + s.steal
 }
 
-// GetUsage calculates CPU usage percentage by sampling /proc/stat twice
-func GetUsage() (float64, error) {
-	// Read initial CPU stats
+// procStat is one /proc/stat snapshot: the aggregate cpu line and every cpuN line
+type procStat struct {
+	aggregate cpuStat
+	cpus      map[int]cpuStat
+}
+
+// statSample holds the two most recent /proc/stat snapshots used for delta-based metrics
+type statSample struct {
+	prev, cur procStat
+	taken     time.Time
+}
+
+// minSampleWindow is the shortest window usage is computed over
+const minSampleWindow = 100 * time.Millisecond
+
+// lastSample keeps the previous snapshot so long-running processes (--watch)
+// compute usage over the whole interval instead of sleeping on every call
+var lastSample *statSample
+
+// readCPUStat reads the aggregate and per-CPU lines from /proc/stat
+func readCPUStat() (procStat, error) {
 	data, err := os.ReadFile("/proc/stat")
 	if err != nil {
-		return 0, err
+		return procStat{}, err
 	}
-	
+
 	lines := strings.Split(string(data), "\n")
 	if len(lines) == 0 {
-		return 0, errors.New("no CPU stat data")
+		return procStat{}, errors.New("no CPU stat data")
 	}
-	
-	stat1, err := parseCPUStat(lines[0])
+
+	aggregate, err := parseCPUStat(lines[0])
 	if err != nil {
-		return 0, err
+		return procStat{}, err
 	}
-	
-	// Wait 100ms
-	time.Sleep(100 * time.Millisecond)
-	
-	// Read CPU stats again
-	data, err = os.ReadFile("/proc/stat")
+
+	stat := procStat{aggregate: aggregate, cpus: make(map[int]cpuStat)}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, "cpu") {
+			break
+		}
+		id, err := strconv.Atoi(strings.TrimPrefix(strings.Fields(line)[0], "cpu"))
+		if err != nil {
+			continue
+		}
+		if cpu, err := parseCPUStat(line); err == nil {
+			stat.cpus[id] = cpu
+		}
+	}
+
+	return stat, nil
+}
+
+// sampleCPUStat returns two /proc/stat snapshots at least minSampleWindow apart.
+// The previous snapshot is reused when available, from memory or from the
+// state file left by the previous invocation; otherwise it samples twice with
+// a short sleep in between.
+func sampleCPUStat() (procStat, procStat, error) {
+	if lastSample != nil && time.Since(lastSample.taken) < minSampleWindow {
+		// Too recent to produce a meaningful delta, reuse the last window
+		return lastSample.prev, lastSample.cur, nil
+	}
+
+	var prev procStat
+	var prevTaken time.Time
+	if lastSample != nil {
+		prev, prevTaken = lastSample.cur, lastSample.taken
+	} else if saved, taken, ok := loadBaseline(); ok {
+		prev, prevTaken = saved, taken
+	} else {
+		stat, err := readCPUStat()
+		if err != nil {
+			return procStat{}, procStat{}, err
+		}
+		prev, prevTaken = stat, time.Now()
+
+		// Wait 100ms
+		time.Sleep(minSampleWindow)
+	}
+
+	cur, err := readCPUStat()
 	if err != nil {
-		return 0, err
+		return procStat{}, procStat{}, err
 	}
-	
-	lines = strings.Split(string(data), "\n")
-	if len(lines) == 0 {
-		return 0, errors.New("no CPU stat data")
+
+	// Counters going backwards means the saved sample predates a reboot
+	if cur.aggregate.total() < prev.aggregate.total() {
+		prev, prevTaken = cur, time.Now()
+		time.Sleep(minSampleWindow)
+		if cur, err = readCPUStat(); err != nil {
+			return procStat{}, procStat{}, err
+		}
 	}
-	
-	stat2, err := parseCPUStat(lines[0])
+
+	lastSample = &statSample{prev: prev, cur: cur, taken: time.Now()}
+	saveSample(prev, prevTaken, cur, lastSample.taken)
+	return prev, cur, nil
+}
+
+// GetUsage calculates CPU usage percentage from two /proc/stat samples
+func GetUsage() (float64, error) {
+	sample1, sample2, err := sampleCPUStat()
 	if err != nil {
 		return 0, err
 	}
+	stat1, stat2 := sample1.aggregate, sample2.aggregate
 	
 	// Calculate usage percentage
 	totalDiff := stat2.total() - stat1.total()
@@ -149,48 +253,54 @@ func GetUsage() (float64, error) {
 	return usage, nil
 }
 
-// GetTemperature reads CPU temperature from k10temp sensor in Celsius
+// GetTemperature reads the CPU Tctl temperature from the k10temp sensor in Celsius
 func GetTemperature() (int, error) {
-	if cpuPaths == nil || cpuPaths.HwMon == "" {
-		return 0, errors.New("CPU hwmon path not available")
-	}
-	
-	tempFile := filepath.Clean(filepath.Join(cpuPaths.HwMon, "temp1_input"))
-	// Validate that the path is within expected system directory and doesn't contain path traversal
-	if !strings.HasPrefix(tempFile, "/sys/") || strings.Contains(tempFile, "..") {
-		return 0, errors.New("invalid system path")
-	}
-	
-	tempData, err := os.ReadFile(tempFile) // #nosec G304 - path is validated above
+	sensors, err := GetTempSensors()
 	if err != nil {
 		return 0, err
 	}
 	
-	tempMillidegrees, err := strconv.ParseInt(strings.TrimSpace(string(tempData)), 10, 64)
+	return sensors.Tctl, nil
+}
+
+// GetFrequency returns average CPU frequency across all cores in GHz
+func GetFrequency() (float64, error) {
+	freqs, err := readCoreFrequencies()
 	if err != nil {
 		return 0, err
 	}
 	
-	return int(tempMillidegrees / 1000), nil
+	var totalFreq float64
+	for _, freqKHz := range freqs {
+		totalFreq += freqKHz
+	}
+	
+	// Convert kHz to GHz and return average
+	avgFreqGHz := (totalFreq / float64(len(freqs))) / 1000000
+	return avgFreqGHz, nil
 }
 
-// GetFrequency returns average CPU frequency across all cores in GHz
-func GetFrequency() (float64, error) {
+// readCoreFrequencies reads scaling_cur_freq of every logical CPU in kHz
+func readCoreFrequencies() (map[int]float64, error) {
+	return readCoreFreqFile("scaling_cur_freq")
+}
+
+// readCoreFreqFile reads one cpufreq file of every logical CPU in kHz
+func readCoreFreqFile(name string) (map[int]float64, error) {
 	if cpuPaths == nil || cpuPaths.CPUFreqBase == "" {
-		return 0, errors.New("CPU frequency path not available")
+		return nil, errors.New("CPU frequency path not available")
 	}
 	
-	cpuDirs, err := filepath.Glob(filepath.Join(cpuPaths.CPUFreqBase, "cpu*/cpufreq/scaling_cur_freq"))
+	cpuDirs, err := filepath.Glob(filepath.Join(cpuPaths.CPUFreqBase, "cpu*/cpufreq", name))
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 	
 	if len(cpuDirs) == 0 {
-		return 0, errors.New("no CPU frequency info available")
+		return nil, errors.New("no CPU frequency info available")
 	}
 	
-	var totalFreq float64
-	var count int
+	freqs := make(map[int]float64, len(cpuDirs))
 	
 	for _, freqFile := range cpuDirs {
 		cleanPath := filepath.Clean(freqFile)
@@ -198,6 +308,10 @@ func GetFrequency() (float64, error) {
 		if !strings.HasPrefix(cleanPath, "/sys/devices/system/cpu/") || strings.Contains(cleanPath, "..") {
 			continue
 		}
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(filepath.Dir(cleanPath))), "cpu"))
+		if err != nil {
+			continue
+		}
 		data, err := os.ReadFile(cleanPath) // #nosec G304 - path is validated above
 		if err != nil {
 			continue
@@ -208,17 +322,14 @@ func GetFrequency() (float64, error) {
 			continue
 		}
 		
-		totalFreq += freqKHz
-		count++
+		freqs[id] = freqKHz
 	}
 	
-	if count == 0 {
-		return 0, errors.New("no valid CPU frequency data")
+	if len(freqs) == 0 {
+		return nil, errors.New("no valid CPU frequency data")
 	}
 	
-	// Convert kHz to GHz and return average
-	avgFreqGHz := (totalFreq / float64(count)) / 1000000
-	return avgFreqGHz, nil
+	return freqs, nil
 }
 
 // GetCores returns the number of CPU cores available
@@ -343,51 +454,83 @@ func GetMinMaxFreq() (float64, float64, error) {
 	return minFreqKHz / 1000000, maxFreqKHz / 1000000, nil
 }
 
+// GetMinFreq returns the minimum CPU frequency in GHz
+func GetMinFreq() (float64, error) {
+	minFreq, _, err := GetMinMaxFreq()
+	return minFreq, err
+}
+
+// GetMaxFreq returns the maximum CPU frequency in GHz
+func GetMaxFreq() (float64, error) {
+	_, maxFreq, err := GetMinMaxFreq()
+	return maxFreq, err
+}
+
 // GetIOWait calculates the percentage of time spent waiting for I/O operations
 func GetIOWait() (float64, error) {
-	data, err := os.ReadFile("/proc/stat")
-	if err != nil {
-		return 0, err
-	}
-	
-	lines := strings.Split(string(data), "\n")
-	if len(lines) == 0 {
-		return 0, errors.New("no CPU stat data")
-	}
-	
-	stat1, err := parseCPUStat(lines[0])
+	sample1, sample2, err := sampleCPUStat()
 	if err != nil {
 		return 0, err
 	}
+	stat1, stat2 := sample1.aggregate, sample2.aggregate
 	
-	time.Sleep(100 * time.Millisecond)
-	
-	data, err = os.ReadFile("/proc/stat")
-	if err != nil {
-		return 0, err
-	}
+	totalDiff := stat2.total() - stat1.total()
+	iowaitDiff := stat2.iowait - stat1.iowait
 	
-	lines = strings.Split(string(data), "\n")
-	if len(lines) == 0 {
-		return 0, errors.New("no CPU stat data")
+	if totalDiff == 0 {
+		return 0, nil
 	}
 	
-	stat2, err := parseCPUStat(lines[0])
+	return float64(iowaitDiff) / float64(totalDiff) * 100, nil
+}
+
+
+// UsageBreakdown is the share of CPU time spent in each /proc/stat state
+// over the sample window, in percent. Guest time is also part of user and
+// guest_nice of nice, as the kernel accounts it.
+type UsageBreakdown struct {
+	User      float64 `json:"user"`
+	Nice      float64 `json:"nice"`
+	System    float64 `json:"system"`
+	IRQ       float64 `json:"irq"`
+	SoftIRQ   float64 `json:"softirq"`
+	Steal     float64 `json:"steal"`
+	Guest     float64 `json:"guest"`
+	GuestNice float64 `json:"guest_nice"`
+}
+
+// GetUsageBreakdown returns the share of each CPU state from two /proc/stat samples
+func GetUsageBreakdown() (UsageBreakdown, error) {
+	sample1, sample2, err := sampleCPUStat()
 	if err != nil {
-		return 0, err
+		return UsageBreakdown{}, err
 	}
+	stat1, stat2 := sample1.aggregate, sample2.aggregate
 	
 	totalDiff := stat2.total() - stat1.total()
-	iowaitDiff := stat2.iowait - stat1.iowait
-	
 	if totalDiff == 0 {
-		return 0, nil
+		return UsageBreakdown{}, nil
 	}
 	
-	return float64(iowaitDiff) / float64(totalDiff) * 100, nil
+	share := func(before, after uint64) float64 {
+		if after < before {
+			return 0
+		}
+		return float64(after-before) / float64(totalDiff) * 100
+	}
+	
+	return UsageBreakdown{
+		User:      share(stat1.user, stat2.user),
+		Nice:      share(stat1.nice, stat2.nice),
+		System:    share(stat1.system, stat2.system),
+		IRQ:       share(stat1.irq, stat2.irq),
+		SoftIRQ:   share(stat1.softirq, stat2.softirq),
+		Steal:     share(stat1.steal, stat2.steal),
+		Guest:     share(stat1.guest, stat2.guest),
+		GuestNice: share(stat1.guestNice, stat2.guestNice),
+	}, nil
 }
 
-
 // GetPower returns overall system power consumption in watts from battery/AC adapter
 // Positive values indicate power being added to battery (charging)
 // Negative values indicate power being consumed from battery (discharging)
@@ -514,6 +657,10 @@ func GetLowestNonlinearFreq() (float64, error) {
 
 // GetAllMetrics collects all CPU metrics and returns them in a single structure
 func GetAllMetrics() (*Metrics, error) {
+	// Optional fields fall back to a placeholder, failed records why so the
+	// daemon can report them as unavailable
+	failed := make(map[string]string)
+
 	usage, err := GetUsage()
 	if err != nil {
 		return nil, err
@@ -537,63 +684,131 @@ func GetAllMetrics() (*Metrics, error) {
 	memUsage, err := GetMemoryUsage()
 	if err != nil {
 		memUsage = 0 // Don't fail on memory error, just set to 0
+		failed["memory_usage"] = err.Error()
 	}
 	
 	loadAvg, err := GetLoadAverage()
 	if err != nil {
 		loadAvg = 0 // Don't fail on load average error, just set to 0
+		failed["load_avg"] = err.Error()
 	}
 	
 	governor, err := GetGovernor()
 	if err != nil {
 		governor = "unknown" // Don't fail on governor error, just set to unknown
+		failed["governor"] = err.Error()
 	}
 	
 	boostEnabled, err := GetBoostEnabled()
 	if err != nil {
 		boostEnabled = false // Don't fail on boost error, just set to false
+		failed["boost_enabled"] = err.Error()
 	}
 	
 	minFreq, maxFreq, err := GetMinMaxFreq()
 	if err != nil {
 		minFreq, maxFreq = 0, 0 // Don't fail on frequency range error, just set to 0
+		failed["min_freq"] = err.Error()
+		failed["max_freq"] = err.Error()
 	}
 	
 	ioWait, err := GetIOWait()
 	if err != nil {
 		ioWait = 0 // Don't fail on IO wait error, just set to 0
+		failed["io_wait"] = err.Error()
 	}
 	
 	power, err := GetPower()
 	if err != nil {
 		power = 0 // Don't fail on power error, just set to 0
+		failed["power"] = err.Error()
 	}
 	
 	pstateStatus, err := GetPstateStatus()
 	if err != nil {
 		pstateStatus = "not_available" // Don't fail on pstate error, just set to not_available
+		failed["pstate_status"] = err.Error()
 	}
 	
 	pstatePrefcore, err := GetPstatePrefcore()
 	if err != nil {
 		pstatePrefcore = "not_available" // Don't fail on prefcore error, just set to not_available
+		failed["pstate_prefcore"] = err.Error()
 	}
 	
 	energyPerfPreference, err := GetEnergyPerfPreference()
 	if err != nil {
 		energyPerfPreference = "not_available" // Don't fail on energy perf error, just set to not_available
+		failed["energy_perf_preference"] = err.Error()
 	}
 	
 	highestPerf, err := GetHighestPerf()
 	if err != nil {
 		highestPerf = 0 // Don't fail on highest perf error, just set to 0
+		failed["highest_perf"] = err.Error()
 	}
 	
 	lowestNonlinearFreq, err := GetLowestNonlinearFreq()
 	if err != nil {
 		lowestNonlinearFreq = 0 // Don't fail on lowest freq error, just set to 0
+		failed["lowest_nonlinear_freq"] = err.Error()
 	}
 	
+	breakdown, err := GetUsageBreakdown()
+	if err != nil {
+		breakdown = UsageBreakdown{} // Don't fail on breakdown error, just set to 0
+		failed["usage_breakdown"] = err.Error()
+	}
+	
+	coreStats, err := GetCoreStats()
+	if err != nil {
+		coreStats = nil // Don't fail on per-core error, just leave it empty
+		failed["core_stats"] = err.Error()
+	}
+	
+	policies, err := GetPolicyPstates()
+	if err != nil {
+		policies = nil // Don't fail on pstate error, just leave it empty
+		failed["policies"] = err.Error()
+	}
+	
+	throttle, err := GetThrottleStatus()
+	if err != nil {
+		throttle = ThrottleStatus{} // Don't fail on throttle error, reasons stay unknown
+		failed["throttle"] = err.Error()
+	}
+	
+	sensors, err := GetTempSensors()
+	if err != nil {
+		sensors = TempSensors{Tctl: temp} // Don't fail on sensor error, keep the main temperature
+		failed["temp_sensors"] = err.Error()
+	}
+	
+	raplPower, err := GetRAPLPower()
+	if err != nil {
+		raplPower = nil // Don't fail on RAPL error, package and core power read as 0
+		failed["package_power"] = err.Error()
+		failed["core_power"] = err.Error()
+	} else {
+		// Many parts only expose the package domain
+		for field, domain := range map[string]string{"package_power": "package", "core_power": "core"} {
+			if _, ok := raplPower[domain]; !ok {
+				failed[field] = "RAPL " + domain + " domain not available"
+			}
+		}
+	}
+	
+	// Reuse the per-core sample, a second one would fall inside the sample window
+	classes, err := classifyCores()
+	if err != nil {
+		classes = nil // Don't fail on class error, just leave it empty
+		failed["core_classes"] = err.Error()
+	}
+	classes = CoreClassLoad(classes, coreStats, sensors)
+	
+	ccds := DomainStats(DomainCCD, coreStats, sensors)
+	ccxs := DomainStats(DomainCCX, coreStats, sensors)
+	
 	return &Metrics{
 		Usage:                 usage,
 		Temperature:           temp,
@@ -612,5 +827,16 @@ func GetAllMetrics() (*Metrics, error) {
 		EnergyPerfPreference:  energyPerfPreference,
 		HighestPerf:           highestPerf,
 		LowestNonlinearFreq:   lowestNonlinearFreq,
+		PackagePower:          raplPower["package"],
+		CorePower:             raplPower["core"],
+		CoreStats:             coreStats,
+		Throttle:              throttle,
+		Policies:              policies,
+		CoreClasses:           classes,
+		CCDs:                  ccds,
+		CCXs:                  ccxs,
+		UsageBreakdown:        breakdown,
+		TempSensors:           sensors,
+		Errors:                failed,
 	}, nil
 }
\ No newline at end of file
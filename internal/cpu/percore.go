@@ -0,0 +1,70 @@
+// Package cpu provides per-logical-CPU usage and frequency
+package cpu
+
+import (
+	"errors"
+	"sort"
+)
+
+// CoreStat is the usage and current frequency of one logical CPU
+type CoreStat struct {
+	CPU       int     `json:"cpu"`
+	Usage     float64 `json:"usage"`
+	Frequency float64 `json:"frequency"` // GHz, 0 when cpufreq is not available
+}
+
+// GetCoreStats returns the usage and frequency of every logical CPU, ordered
+// by CPU number
+func GetCoreStats() ([]CoreStat, error) {
+	sample1, sample2, err := sampleCPUStat()
+	if err != nil {
+		return nil, err
+	}
+
+	// Frequencies are optional, virtual machines often lack cpufreq
+	freqs, _ := readCoreFrequencies()
+
+	cores := make([]CoreStat, 0, len(sample2.cpus))
+	for id, stat2 := range sample2.cpus {
+		core := CoreStat{CPU: id, Frequency: freqs[id] / 1000000}
+
+		// CPUs brought online between the samples have no previous line
+		if stat1, ok := sample1.cpus[id]; ok && stat2.total() > stat1.total() {
+			core.Usage = float64(stat2.active()-stat1.active()) / float64(stat2.total()-stat1.total()) * 100
+		}
+
+		cores = append(cores, core)
+	}
+
+	if len(cores) == 0 {
+		return nil, errors.New("no per-CPU stat data")
+	}
+
+	sort.Slice(cores, func(i, j int) bool {
+		return cores[i].CPU < cores[j].CPU
+	})
+
+	return cores, nil
+}
+
+// BusiestCore returns the core with the highest usage
+func BusiestCore(cores []CoreStat) CoreStat {
+	var busiest CoreStat
+	for i, core := range cores {
+		if i == 0 || core.Usage > busiest.Usage {
+			busiest = core
+		}
+	}
+	return busiest
+}
+
+// FastestCore returns the core with the highest current frequency
+func FastestCore(cores []CoreStat) CoreStat {
+	var fastest CoreStat
+	for i, core := range cores {
+		if i == 0 || core.Frequency > fastest.Frequency {
+			fastest = core
+		}
+	}
+	return fastest
+}
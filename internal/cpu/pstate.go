@@ -0,0 +1,139 @@
+// Package cpu provides per-policy AMD pstate inspection
+package cpu
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PolicyPstate holds the amd_pstate values of one cpufreq policy. Numeric
+// values the driver does not expose are 0, missing rankings are -1.
+type PolicyPstate struct {
+	Policy               int     `json:"policy"`
+	CPUs                 []int   `json:"cpus"`
+	EnergyPerfPreference string  `json:"energy_perf_preference"`
+	HighestPerf          int     `json:"highest_perf"`
+	LowestNonlinearFreq  float64 `json:"lowest_nonlinear_freq"` // GHz
+	PrefcoreRanking      int     `json:"prefcore_ranking"`
+	HWPrefcore           bool    `json:"hw_prefcore"` // amd_pstate_hw_prefcore reads "enabled"
+}
+
+// GetPolicyPstates reads the amd_pstate values of every cpufreq policy
+func GetPolicyPstates() ([]PolicyPstate, error) {
+	if cpuPaths == nil || cpuPaths.AMDPstateBase == "" {
+		return nil, errors.New("amd_pstate driver not available")
+	}
+	if len(cpuPaths.Policies) == 0 {
+		return nil, errors.New("no cpufreq policies found")
+	}
+
+	policies := make([]PolicyPstate, 0, len(cpuPaths.Policies))
+	for _, policy := range cpuPaths.Policies {
+		pstate := PolicyPstate{
+			Policy:               policy.Index,
+			CPUs:                 policy.CPUs,
+			EnergyPerfPreference: readPolicyString(policy.Path, "energy_performance_preference"),
+			HighestPerf:          readPolicyInt(policy.Path, "amd_pstate_highest_perf", 0),
+			LowestNonlinearFreq:  float64(readPolicyInt(policy.Path, "amd_pstate_lowest_nonlinear_freq", 0)) / 1000000,
+			PrefcoreRanking:      readPolicyInt(policy.Path, "amd_pstate_prefcore_ranking", -1),
+			HWPrefcore:           readPolicyString(policy.Path, "amd_pstate_hw_prefcore") == "enabled",
+		}
+		policies = append(policies, pstate)
+	}
+
+	return policies, nil
+}
+
+// EnergyPerfPreferences returns the distinct EPP values across policies, in
+// policy order. More than one value means the policies disagree.
+func EnergyPerfPreferences(policies []PolicyPstate) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, policy := range policies {
+		if policy.EnergyPerfPreference == "" || seen[policy.EnergyPerfPreference] {
+			continue
+		}
+		seen[policy.EnergyPerfPreference] = true
+		values = append(values, policy.EnergyPerfPreference)
+	}
+	return values
+}
+
+// PreferredCore is a logical CPU with its preferred-core ranking and current load
+type PreferredCore struct {
+	CPU       int     `json:"cpu"`
+	Ranking   int     `json:"ranking"` // amd_pstate_prefcore_ranking, updated by the firmware
+	Usage     float64 `json:"usage"`
+	Frequency float64 `json:"frequency"` // GHz
+}
+
+// GetPreferredCores returns every CPU ordered by preferred-core ranking,
+// best first
+func GetPreferredCores() ([]PreferredCore, error) {
+	policies, err := GetPolicyPstates()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load is optional, the ranking alone is still useful
+	cores, _ := GetCoreStats()
+
+	preferred := PreferredCores(policies, cores)
+	if len(preferred) == 0 {
+		return nil, errors.New("preferred core ranking not available")
+	}
+	return preferred, nil
+}
+
+// PreferredCores joins policy rankings with per-core load, ordered by
+// ranking, best first. CPUs without a ranking are left out.
+func PreferredCores(policies []PolicyPstate, cores []CoreStat) []PreferredCore {
+	load := make(map[int]CoreStat, len(cores))
+	for _, core := range cores {
+		load[core.CPU] = core
+	}
+
+	var preferred []PreferredCore
+	for _, policy := range policies {
+		if policy.PrefcoreRanking < 0 {
+			continue
+		}
+		for _, id := range policy.CPUs {
+			preferred = append(preferred, PreferredCore{
+				CPU:       id,
+				Ranking:   policy.PrefcoreRanking,
+				Usage:     load[id].Usage,
+				Frequency: load[id].Frequency,
+			})
+		}
+	}
+
+	sort.SliceStable(preferred, func(i, j int) bool {
+		if preferred[i].Ranking != preferred[j].Ranking {
+			return preferred[i].Ranking > preferred[j].Ranking
+		}
+		return preferred[i].CPU < preferred[j].CPU
+	})
+
+	return preferred
+}
+
+func readPolicyString(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name)) // #nosec G304 - dir is a discovered cpufreq policy
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func readPolicyInt(dir, name string, missing int) int {
+	value, err := strconv.Atoi(readPolicyString(dir, name))
+	if err != nil {
+		return missing
+	}
+	return value
+}
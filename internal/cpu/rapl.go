@@ -0,0 +1,220 @@
+// Package cpu provides RAPL package power from powercap energy counters
+package cpu
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// raplDomain is one powercap zone: the package itself or a subdomain such as core
+type raplDomain struct {
+	name     string
+	path     string
+	maxRange uint64 // µJ, energy_uj wraps around past this value
+}
+
+// energySample holds the two most recent energy readings per domain
+type energySample struct {
+	prev, cur map[string]uint64
+	prevTaken time.Time
+	taken     time.Time
+}
+
+// lastEnergy keeps the previous energy readings for long-running processes
+var lastEnergy *energySample
+
+// GetPackagePower returns the CPU package power in watts from RAPL
+func GetPackagePower() (float64, error) {
+	power, err := GetRAPLPower()
+	if err != nil {
+		return 0, err
+	}
+
+	watts, ok := power["package"]
+	if !ok {
+		return 0, errors.New("RAPL package domain not available")
+	}
+	return watts, nil
+}
+
+// GetCorePower returns the power of the RAPL core domain in watts
+func GetCorePower() (float64, error) {
+	power, err := GetRAPLPower()
+	if err != nil {
+		return 0, err
+	}
+
+	watts, ok := power["core"]
+	if !ok {
+		return 0, errors.New("RAPL core domain not available")
+	}
+	return watts, nil
+}
+
+// GetRAPLPower returns the power of every RAPL domain in watts, keyed by
+// domain name (package, core, ...). The energy counters are only readable
+// by root on most kernels.
+func GetRAPLPower() (map[string]float64, error) {
+	domains, err := raplDomains()
+	if err != nil {
+		return nil, err
+	}
+
+	sample, err := sampleEnergy(domains)
+	if err != nil {
+		return nil, err
+	}
+
+	seconds := sample.taken.Sub(sample.prevTaken).Seconds()
+	if seconds <= 0 {
+		return nil, errors.New("empty RAPL sample window")
+	}
+
+	power := make(map[string]float64, len(domains))
+	for _, domain := range domains {
+		prev, okPrev := sample.prev[domain.name]
+		cur, okCur := sample.cur[domain.name]
+		if !okPrev || !okCur {
+			continue
+		}
+
+		var delta uint64
+		if cur >= prev {
+			delta = cur - prev
+		} else {
+			// The counter wrapped around max_energy_range_uj, the last value
+			// before 0
+			delta = domain.maxRange - prev + cur + 1
+		}
+		power[domain.name] = float64(delta) / 1e6 / seconds
+	}
+
+	return power, nil
+}
+
+// raplDomains lists the package zone and its subzones
+func raplDomains() ([]raplDomain, error) {
+	if powerPaths == nil || powerPaths.RAPL == "" {
+		return nil, errors.New("RAPL not available")
+	}
+
+	paths := []string{powerPaths.RAPL}
+	if subzones, err := filepath.Glob(powerPaths.RAPL + "/" + filepath.Base(powerPaths.RAPL) + ":*"); err == nil {
+		paths = append(paths, subzones...)
+	}
+
+	domains := make([]raplDomain, 0, len(paths))
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if data, err := os.ReadFile(filepath.Join(path, "name")); err == nil {
+			name = strings.TrimSpace(string(data))
+		}
+		// The top zone is named package-0
+		if strings.HasPrefix(name, "package") {
+			name = "package"
+		}
+
+		maxRange, err := readEnergy(filepath.Join(path, "max_energy_range_uj"))
+		if err != nil {
+			continue
+		}
+
+		domains = append(domains, raplDomain{name: name, path: path, maxRange: maxRange})
+	}
+
+	if len(domains) == 0 {
+		return nil, errors.New("no RAPL domains found")
+	}
+
+	return domains, nil
+}
+
+// sampleEnergy returns two energy readings at least minSampleWindow apart.
+// The previous reading is reused when available, from memory or from the
+// state file left by the previous invocation; otherwise it samples twice with
+// a short sleep in between.
+func sampleEnergy(domains []raplDomain) (*energySample, error) {
+	if lastEnergy != nil && time.Since(lastEnergy.taken) < minSampleWindow {
+		return lastEnergy, nil
+	}
+
+	var prev map[string]uint64
+	var prevTaken time.Time
+	saved := false
+	if lastEnergy != nil {
+		prev, prevTaken = lastEnergy.cur, lastEnergy.taken
+	} else if energy, taken, ok := loadEnergyBaseline(); ok {
+		prev, prevTaken, saved = energy, taken, true
+	} else {
+		energy, err := readDomainsEnergy(domains)
+		if err != nil {
+			return nil, err
+		}
+		prev, prevTaken = energy, time.Now()
+
+		time.Sleep(minSampleWindow)
+	}
+
+	cur, err := readDomainsEnergy(domains)
+	if err != nil {
+		return nil, err
+	}
+
+	// A saved counter above the current one predates a reboot far more often
+	// than it wrapped, sample again rather than report a bogus wrap
+	if saved && energyDecreased(prev, cur) {
+		prev, prevTaken = cur, time.Now()
+		time.Sleep(minSampleWindow)
+		if cur, err = readDomainsEnergy(domains); err != nil {
+			return nil, err
+		}
+	}
+
+	lastEnergy = &energySample{prev: prev, cur: cur, prevTaken: prevTaken, taken: time.Now()}
+	saveEnergy(prev, prevTaken, cur, lastEnergy.taken)
+	return lastEnergy, nil
+}
+
+// energyDecreased reports whether any counter in cur is below its value in prev
+func energyDecreased(prev, cur map[string]uint64) bool {
+	for name, value := range cur {
+		if before, ok := prev[name]; ok && value < before {
+			return true
+		}
+	}
+	return false
+}
+
+// readDomainsEnergy reads energy_uj of every domain
+func readDomainsEnergy(domains []raplDomain) (map[string]uint64, error) {
+	energy := make(map[string]uint64, len(domains))
+	for _, domain := range domains {
+		value, err := readEnergy(filepath.Join(domain.path, "energy_uj"))
+		if err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				return nil, errors.New("RAPL energy counters are only readable by root: " + err.Error())
+			}
+			continue
+		}
+		energy[domain.name] = value
+	}
+
+	if len(energy) == 0 {
+		return nil, errors.New("no RAPL energy counter readable")
+	}
+
+	return energy, nil
+}
+
+// readEnergy reads a µJ counter
+func readEnergy(path string) (uint64, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is from discovered powercap zones
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
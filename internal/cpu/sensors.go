@@ -0,0 +1,158 @@
+// Package cpu provides labelled k10temp temperature sensors
+package cpu
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CCDTemp is the temperature of one core complex die
+type CCDTemp struct {
+	CCD         int `json:"ccd"`
+	Temperature int `json:"temperature"`
+}
+
+// TempSensors holds the labelled k10temp readings in Celsius. Tctl is the
+// control temperature, offset on some Threadripper and Ryzen parts; Tdie is
+// the real die temperature when the part applies such an offset.
+type TempSensors struct {
+	Tctl     int       `json:"tctl"`
+	Tdie     int       `json:"tdie,omitempty"`
+	CCDTemps []CCDTemp `json:"ccd_temps,omitempty"`
+}
+
+// ValidateSensor checks a sensor name accepted by GetSensorTemperature:
+// tctl, tdie, ccdN or max
+func ValidateSensor(sensor string) error {
+	switch sensor {
+	case "tctl", "tdie", "max":
+		return nil
+	}
+	if n, err := strconv.Atoi(strings.TrimPrefix(sensor, "ccd")); err == nil && strings.HasPrefix(sensor, "ccd") && n > 0 {
+		return nil
+	}
+	return errors.New("unknown sensor " + sensor + ", expected tctl, tdie, ccdN or max")
+}
+
+// Sensor picks one temperature by name. max is the hottest CCD, falling back
+// to Tdie and then Tctl when the CPU does not report CCD temperatures.
+func (t TempSensors) Sensor(sensor string) (int, error) {
+	if err := ValidateSensor(sensor); err != nil {
+		return 0, err
+	}
+
+	switch sensor {
+	case "tctl":
+		return t.Tctl, nil
+	case "tdie":
+		if t.Tdie == 0 {
+			return 0, errors.New("Tdie not reported by this CPU")
+		}
+		return t.Tdie, nil
+	case "max":
+		if len(t.CCDTemps) == 0 {
+			if t.Tdie > 0 {
+				return t.Tdie, nil
+			}
+			return t.Tctl, nil
+		}
+		hottest := t.CCDTemps[0].Temperature
+		for _, ccd := range t.CCDTemps[1:] {
+			hottest = max(hottest, ccd.Temperature)
+		}
+		return hottest, nil
+	}
+
+	n, _ := strconv.Atoi(strings.TrimPrefix(sensor, "ccd"))
+	for _, ccd := range t.CCDTemps {
+		if ccd.CCD == n {
+			return ccd.Temperature, nil
+		}
+	}
+	return 0, errors.New(sensor + " not reported by this CPU")
+}
+
+// GetTempSensors reads every labelled temperature of the CPU hwmon. Inputs
+// are resolved by tempN_label; a hwmon without labels reports temp1 as Tctl.
+func GetTempSensors() (TempSensors, error) {
+	if cpuPaths == nil || cpuPaths.HwMon == "" {
+		return TempSensors{}, errors.New("CPU hwmon path not available")
+	}
+
+	inputs, err := filepath.Glob(filepath.Join(cpuPaths.HwMon, "temp*_input"))
+	if err != nil {
+		return TempSensors{}, err
+	}
+
+	var sensors TempSensors
+	found := false
+	for _, input := range inputs {
+		label := "tctl"
+		if data, err := os.ReadFile(strings.TrimSuffix(input, "_input") + "_label"); err == nil {
+			label = strings.ToLower(strings.TrimSpace(string(data)))
+		} else if filepath.Base(input) != "temp1_input" {
+			continue
+		}
+
+		temp, err := readTempInput(input)
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case label == "tctl":
+			sensors.Tctl = temp
+			found = true
+		case label == "tdie":
+			sensors.Tdie = temp
+		case strings.HasPrefix(label, "tccd"):
+			if n, err := strconv.Atoi(strings.TrimPrefix(label, "tccd")); err == nil {
+				sensors.CCDTemps = append(sensors.CCDTemps, CCDTemp{CCD: n, Temperature: temp})
+			}
+		}
+	}
+
+	if !found {
+		return TempSensors{}, errors.New("no Tctl temperature found")
+	}
+
+	sort.Slice(sensors.CCDTemps, func(i, j int) bool {
+		return sensors.CCDTemps[i].CCD < sensors.CCDTemps[j].CCD
+	})
+
+	return sensors, nil
+}
+
+// GetSensorTemperature returns one temperature by name: tctl, tdie, ccdN or max
+func GetSensorTemperature(sensor string) (int, error) {
+	sensors, err := GetTempSensors()
+	if err != nil {
+		return 0, err
+	}
+	return sensors.Sensor(sensor)
+}
+
+// readTempInput reads a hwmon millidegree input in Celsius
+func readTempInput(path string) (int, error) {
+	tempFile := filepath.Clean(path)
+	// Validate that the path is within expected system directory and doesn't contain path traversal
+	if !strings.HasPrefix(tempFile, "/sys/") || strings.Contains(tempFile, "..") {
+		return 0, errors.New("invalid system path")
+	}
+
+	tempData, err := os.ReadFile(tempFile) // #nosec G304 - path is validated above
+	if err != nil {
+		return 0, err
+	}
+
+	tempMillidegrees, err := strconv.ParseInt(strings.TrimSpace(string(tempData)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(tempMillidegrees / 1000), nil
+}
@@ -0,0 +1,190 @@
+// Package cpu persists the previous /proc/stat and RAPL samples between invocations
+package cpu
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// stateVersion is bumped whenever the saved sample layout changes so old
+// state files are ignored
+const stateVersion = 3
+
+// maxStateAge is the oldest saved sample usage is computed against. Older
+// samples (after suspend, or when Waybar was not running) would average over
+// an unrelated period.
+const maxStateAge = time.Minute
+
+// savedStat is one /proc/stat snapshot as stored on disk
+type savedStat struct {
+	Taken     time.Time        `json:"taken"`
+	Aggregate []uint64         `json:"aggregate"`
+	CPUs      map[int][]uint64 `json:"cpus"`
+}
+
+// savedEnergy is one reading of the RAPL energy counters in µJ, by domain
+type savedEnergy struct {
+	Taken   time.Time         `json:"taken"`
+	Domains map[string]uint64 `json:"domains"`
+}
+
+// savedState holds the two most recent snapshots. Several Waybar modules run
+// this CLI at the same interval, so the newest snapshot is often only a few
+// milliseconds old; the older one then serves as the baseline.
+type savedState struct {
+	Version   int           `json:"version"`
+	Snapshots []savedStat   `json:"snapshots"`
+	Energy    []savedEnergy `json:"energy,omitempty"`
+}
+
+// statePath returns the state file path, under $XDG_RUNTIME_DIR when set
+func statePath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return filepath.Join(os.TempDir(), "waybar-amd-module-"+strconv.Itoa(os.Getuid())+"-cpu.json")
+	}
+	return filepath.Join(runtimeDir, "waybar-amd-module-cpu.json")
+}
+
+// loadState reads the state file, returning an empty state when it is
+// missing or from another version
+func loadState() savedState {
+	data, err := os.ReadFile(statePath())
+	if err != nil {
+		return savedState{Version: stateVersion}
+	}
+
+	var state savedState
+	if err := json.Unmarshal(data, &state); err != nil || state.Version != stateVersion {
+		return savedState{Version: stateVersion}
+	}
+	return state
+}
+
+// usableBaseline reports whether a snapshot taken at taken is at least
+// minSampleWindow and at most maxStateAge old
+func usableBaseline(taken time.Time) bool {
+	age := time.Since(taken)
+	return age >= minSampleWindow && age <= maxStateAge
+}
+
+// loadBaseline returns the newest saved snapshot at least minSampleWindow
+// and at most maxStateAge old
+func loadBaseline() (procStat, time.Time, bool) {
+	state := loadState()
+	for i := len(state.Snapshots) - 1; i >= 0; i-- {
+		saved := state.Snapshots[i]
+		if !usableBaseline(saved.Taken) {
+			continue
+		}
+
+		stat, ok := saved.procStat()
+		if !ok {
+			return procStat{}, time.Time{}, false
+		}
+		return stat, saved.Taken, true
+	}
+
+	return procStat{}, time.Time{}, false
+}
+
+// loadEnergyBaseline returns the newest saved RAPL reading at least
+// minSampleWindow and at most maxStateAge old
+func loadEnergyBaseline() (map[string]uint64, time.Time, bool) {
+	state := loadState()
+	for i := len(state.Energy) - 1; i >= 0; i-- {
+		if saved := state.Energy[i]; usableBaseline(saved.Taken) && len(saved.Domains) > 0 {
+			return saved.Domains, saved.Taken, true
+		}
+	}
+	return nil, time.Time{}, false
+}
+
+// saveSample stores the baseline and current /proc/stat snapshots, keeping
+// the RAPL readings of the state file
+func saveSample(prev procStat, prevTaken time.Time, cur procStat, taken time.Time) {
+	updateState(func(state *savedState) {
+		state.Snapshots = []savedStat{
+			newSavedStat(prev, prevTaken),
+			newSavedStat(cur, taken),
+		}
+	})
+}
+
+// saveEnergy stores the baseline and current RAPL readings, keeping the
+// /proc/stat snapshots of the state file
+func saveEnergy(prev map[string]uint64, prevTaken time.Time, cur map[string]uint64, taken time.Time) {
+	updateState(func(state *savedState) {
+		state.Energy = []savedEnergy{
+			{Taken: prevTaken, Domains: prev},
+			{Taken: taken, Domains: cur},
+		}
+	})
+}
+
+// updateState reads, updates and writes the state file under an exclusive
+// lock, so concurrent modules never drop each other's section. The lock is a
+// separate file as saveState replaces the state file.
+func updateState(update func(*savedState)) {
+	lock, err := os.OpenFile(statePath()+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err == nil {
+		defer func() { _ = lock.Close() }()
+		if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err == nil {
+			defer func() { _ = syscall.Flock(int(lock.Fd()), syscall.LOCK_UN) }()
+		}
+	}
+
+	state := loadState()
+	update(&state)
+	saveState(state)
+}
+
+// saveState writes the state file. Errors are ignored: the next call simply
+// falls back to sampling twice.
+func saveState(state savedState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	// Write then rename so concurrent readers never see a partial file
+	path := statePath()
+	tmp := path + "." + strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+func newSavedStat(stat procStat, taken time.Time) savedStat {
+	saved := savedStat{
+		Taken:     taken,
+		Aggregate: stat.aggregate.values(),
+		CPUs:      make(map[int][]uint64, len(stat.cpus)),
+	}
+	for id, cpu := range stat.cpus {
+		saved.CPUs[id] = cpu.values()
+	}
+	return saved
+}
+
+func (s savedStat) procStat() (procStat, bool) {
+	aggregate, ok := cpuStatFromValues(s.Aggregate)
+	if !ok {
+		return procStat{}, false
+	}
+
+	stat := procStat{aggregate: aggregate, cpus: make(map[int]cpuStat, len(s.CPUs))}
+	for id, values := range s.CPUs {
+		if cpu, ok := cpuStatFromValues(values); ok {
+			stat.cpus[id] = cpu
+		}
+	}
+	return stat, true
+}
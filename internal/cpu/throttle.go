@@ -0,0 +1,163 @@
+// Package cpu provides CPU throttling detection
+package cpu
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Throttle reasons reported by GetThrottleStatus
+const (
+	ThrottleThermal = "thermal"
+	ThrottlePower   = "power limit"
+	ThrottleUserCap = "user cap"
+)
+
+// A core busier than throttleLoad running below throttleRatio of its
+// scaling_max_freq is held back by the package power limit (PPT/TDC/EDC)
+const (
+	throttleLoad  = 90.0
+	throttleRatio = 0.85
+)
+
+// lastThrottleEvents keeps the thermal_throttle counters seen by long-running
+// processes, so only new events count as throttling
+var lastThrottleEvents *uint64
+
+// ThrottleStatus describes why the CPU runs below its maximum frequency
+type ThrottleStatus struct {
+	Reasons        []string `json:"reasons"`         // empty when not throttled
+	MaxFreq        float64  `json:"max_freq"`        // GHz, cpuinfo_max_freq
+	CapFreq        float64  `json:"cap_freq"`        // GHz, lowest scaling_max_freq
+	PeakFreq       float64  `json:"peak_freq"`       // GHz, fastest core
+	Temperature    int      `json:"temperature"`     // Tctl
+	ThermalLimit   int      `json:"thermal_limit"`   // °C, 0 when the hwmon has no temp1_crit
+	ThrottleEvents uint64   `json:"throttle_events"` // thermal_throttle events since boot
+}
+
+// GetThrottleStatus compares per-core frequencies against their limits and
+// Tctl against the thermal limit. Zen 4 and later run at Tjmax by design under
+// load, so without a published limit temperature alone never counts as
+// thermal throttling.
+func GetThrottleStatus() (ThrottleStatus, error) {
+	curFreqs, err := readCoreFrequencies()
+	if err != nil {
+		return ThrottleStatus{}, err
+	}
+	capFreqs, err := readCoreFreqFile("scaling_max_freq")
+	if err != nil {
+		return ThrottleStatus{}, err
+	}
+	maxFreqs, err := readCoreFreqFile("cpuinfo_max_freq")
+	if err != nil {
+		return ThrottleStatus{}, err
+	}
+
+	status := ThrottleStatus{Reasons: []string{}, CapFreq: math.Inf(1), ThermalLimit: thermalLimit()}
+	userCap := false
+	for id, maxFreq := range maxFreqs {
+		status.MaxFreq = max(status.MaxFreq, maxFreq/1000000)
+		if capFreq, ok := capFreqs[id]; ok {
+			status.CapFreq = min(status.CapFreq, capFreq/1000000)
+			if capFreq < maxFreq*0.99 {
+				userCap = true
+			}
+		}
+	}
+	if math.IsInf(status.CapFreq, 1) {
+		status.CapFreq = status.MaxFreq
+	}
+	for _, curFreq := range curFreqs {
+		status.PeakFreq = max(status.PeakFreq, curFreq/1000000)
+	}
+
+	thermal := false
+	if temp, err := GetTemperature(); err == nil {
+		status.Temperature = temp
+		thermal = status.ThermalLimit > 0 && temp >= status.ThermalLimit
+	}
+
+	// thermal_throttle counters only exist on Intel, and are cumulative
+	if events, ok := readThrottleEvents(); ok {
+		status.ThrottleEvents = events
+		if lastThrottleEvents != nil && events > *lastThrottleEvents {
+			thermal = true
+		}
+		lastThrottleEvents = &events
+	}
+
+	power := false
+	if !thermal {
+		if cores, err := GetCoreStats(); err == nil {
+			for _, core := range cores {
+				capFreq, ok := capFreqs[core.CPU]
+				if ok && core.Usage >= throttleLoad && core.Frequency*1000000 < capFreq*throttleRatio {
+					power = true
+					break
+				}
+			}
+		}
+	}
+
+	if thermal {
+		status.Reasons = append(status.Reasons, ThrottleThermal)
+	}
+	if power {
+		status.Reasons = append(status.Reasons, ThrottlePower)
+	}
+	if userCap {
+		status.Reasons = append(status.Reasons, ThrottleUserCap)
+	}
+
+	return status, nil
+}
+
+// GetThrottleReasons returns the active CPU throttle reasons, empty when none
+func GetThrottleReasons() ([]string, error) {
+	status, err := GetThrottleStatus()
+	if err != nil {
+		return nil, err
+	}
+	return status.Reasons, nil
+}
+
+// thermalLimit reads temp1_crit from the CPU hwmon, returning 0 when the
+// hwmon does not publish it
+func thermalLimit() int {
+	if cpuPaths == nil || cpuPaths.HwMon == "" {
+		return 0
+	}
+
+	if limit, err := readTempInput(filepath.Join(cpuPaths.HwMon, "temp1_crit")); err == nil && limit > 0 {
+		return limit
+	}
+	return 0
+}
+
+// readThrottleEvents sums the core and package thermal_throttle counters
+func readThrottleEvents() (uint64, bool) {
+	if cpuPaths == nil || cpuPaths.CPUFreqBase == "" {
+		return 0, false
+	}
+
+	files, err := filepath.Glob(filepath.Join(cpuPaths.CPUFreqBase, "cpu[0-9]*/thermal_throttle/*_throttle_count"))
+	if err != nil || len(files) == 0 {
+		return 0, false
+	}
+
+	var events uint64
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 - path is from the cpu sysfs tree
+		if err != nil {
+			continue
+		}
+		if count, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			events += count
+		}
+	}
+
+	return events, true
+}
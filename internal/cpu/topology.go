@@ -0,0 +1,182 @@
+// Package cpu provides per-CCD and per-CCX aggregation of core metrics
+package cpu
+
+import (
+	"errors"
+	"slices"
+	"sort"
+)
+
+// Domain levels accepted by DomainStats
+const (
+	DomainCCD = "ccd"
+	DomainCCX = "ccx"
+)
+
+// DomainStat aggregates the cores of one CCD or CCX
+type DomainStat struct {
+	Domain      int     `json:"domain"`
+	CPUs        []int   `json:"cpus"`
+	Usage       float64 `json:"usage"`       // average over the domain
+	Frequency   float64 `json:"frequency"`   // GHz, average over the domain
+	PeakFreq    float64 `json:"peak_freq"`   // GHz, fastest core of the domain
+	Temperature int     `json:"temperature"` // Tccd, 0 when unknown
+	L3Size      int     `json:"l3_size"`     // KiB
+	VCache      bool    `json:"vcache"`      // L3 larger than the other domains
+}
+
+// ValidateDomain checks a --per value
+func ValidateDomain(level string) error {
+	switch level {
+	case "", DomainCCD, DomainCCX:
+		return nil
+	default:
+		return errors.New("invalid domain: " + level + " (must be ccd or ccx)")
+	}
+}
+
+// GetDomainStats returns usage and frequency per CCD or CCX
+func GetDomainStats(level string) ([]DomainStat, error) {
+	if cpuPaths == nil || len(cpuPaths.Topology) == 0 {
+		return nil, errors.New("CPU topology not available")
+	}
+
+	cores, err := GetCoreStats()
+	if err != nil {
+		return nil, err
+	}
+
+	// Temperatures are optional, many mobile parts only report Tctl
+	sensors, _ := GetTempSensors()
+
+	return DomainStats(level, cores, sensors), nil
+}
+
+// Domains returns the CCD or CCX stats of a metrics sample
+func (m *Metrics) Domains(level string) []DomainStat {
+	if level == DomainCCX {
+		return m.CCXs
+	}
+	return m.CCDs
+}
+
+// DomainStats groups per-core stats by CCD or CCX using the cached topology
+func DomainStats(level string, cores []CoreStat, sensors TempSensors) []DomainStat {
+	if cpuPaths == nil {
+		return nil
+	}
+
+	load := make(map[int]CoreStat, len(cores))
+	for _, core := range cores {
+		load[core.CPU] = core
+	}
+
+	byDomain := make(map[int]*DomainStat)
+	counts := make(map[int]int)
+	for _, topo := range cpuPaths.Topology {
+		id := topo.CCD
+		if level == DomainCCX {
+			id = topo.CCX
+		}
+
+		domain, ok := byDomain[id]
+		if !ok {
+			domain = &DomainStat{Domain: id}
+			byDomain[id] = domain
+		}
+		domain.CPUs = append(domain.CPUs, topo.CPU)
+		domain.L3Size = max(domain.L3Size, topo.L3Size)
+
+		if core, ok := load[topo.CPU]; ok {
+			domain.Usage += core.Usage
+			domain.Frequency += core.Frequency
+			domain.PeakFreq = max(domain.PeakFreq, core.Frequency)
+			counts[id]++
+		}
+	}
+
+	domains := make([]DomainStat, 0, len(byDomain))
+	for id, domain := range byDomain {
+		if counts[id] > 0 {
+			domain.Usage /= float64(counts[id])
+			domain.Frequency /= float64(counts[id])
+		}
+		// Tccd sensors only map onto CCDs
+		if level != DomainCCX {
+			domain.Temperature = ccdTemperature(sensors, id)
+		}
+		domains = append(domains, *domain)
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i].Domain < domains[j].Domain
+	})
+
+	markVCache(domains)
+	return domains
+}
+
+// markVCache flags the domains whose L3 is larger than the smallest one,
+// which is how the 3D V-Cache die of an X3D part shows up
+func markVCache(domains []DomainStat) {
+	if len(domains) < 2 {
+		return
+	}
+
+	smallest := domains[0].L3Size
+	for _, domain := range domains[1:] {
+		smallest = min(smallest, domain.L3Size)
+	}
+	for i := range domains {
+		domains[i].VCache = smallest > 0 && domains[i].L3Size > smallest
+	}
+}
+
+// ccdTemperature returns the Tccd reading of a CCD, or 0 when not reported.
+// k10temp labels Tccd by physical slot, skipping fused-off CCDs, and the CPU
+// hwmon covers the first package only: the nth Tccd reading therefore
+// belongs to the nth CCD of that package.
+func ccdTemperature(sensors TempSensors, ccd int) int {
+	if cpuPaths == nil || len(sensors.CCDTemps) == 0 {
+		return 0
+	}
+
+	firstPackage := -1
+	for _, topo := range cpuPaths.Topology {
+		if firstPackage < 0 || topo.Package < firstPackage {
+			firstPackage = topo.Package
+		}
+	}
+
+	var ccds []int
+	for _, topo := range cpuPaths.Topology {
+		if topo.Package == firstPackage && !slices.Contains(ccds, topo.CCD) {
+			ccds = append(ccds, topo.CCD)
+		}
+	}
+	sort.Ints(ccds)
+
+	temps := slices.Clone(sensors.CCDTemps)
+	sort.Slice(temps, func(i, j int) bool {
+		return temps[i].CCD < temps[j].CCD
+	})
+
+	index := slices.Index(ccds, ccd)
+	if index < 0 || index >= len(temps) {
+		return 0
+	}
+	return temps[index].Temperature
+}
+
+// coreCCDs maps each logical CPU to its CCD
+func coreCCDs() map[int]int {
+	if cpuPaths == nil {
+		return nil
+	}
+
+	ccds := make(map[int]int, len(cpuPaths.Topology))
+	for _, topo := range cpuPaths.Topology {
+		ccds[topo.CPU] = topo.CCD
+	}
+	return ccds
+}
@@ -0,0 +1,196 @@
+// Package daemon provides a shared metrics sampler served over a Unix socket
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bnema/waybar-amd-module/internal/cpu"
+	"github.com/bnema/waybar-amd-module/internal/gpu"
+)
+
+const (
+	socketName  = "waybar-amd-module.sock"
+	dialTimeout = 200 * time.Millisecond
+)
+
+// Snapshot is one sample of every metric, as served to clients
+type Snapshot struct {
+	Timestamp time.Time      `json:"timestamp"`
+	Interval  time.Duration  `json:"interval"`
+	CPU       *cpu.Metrics   `json:"cpu,omitempty"`
+	GPUs      []*gpu.Metrics `json:"gpus,omitempty"`
+}
+
+// Stale reports whether the snapshot is too old to be trusted, e.g. because
+// the daemon is stuck or was suspended with the machine
+func (s *Snapshot) Stale() bool {
+	return time.Since(s.Timestamp) > 2*s.Interval+time.Second
+}
+
+// GPU returns the metrics of the GPU at the given PCI address, or nil
+func (s *Snapshot) GPU(pci string) *gpu.Metrics {
+	for _, metrics := range s.GPUs {
+		if metrics.PCI == pci {
+			return metrics
+		}
+	}
+	return nil
+}
+
+// SocketPath returns the Unix socket path, under $XDG_RUNTIME_DIR when set
+func SocketPath() string {
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return filepath.Join(os.TempDir(), "waybar-amd-module-"+strconv.Itoa(os.Getuid())+".sock")
+	}
+	return filepath.Join(runtimeDir, socketName)
+}
+
+// Server samples all metrics once per interval and serves the latest snapshot
+type Server struct {
+	interval time.Duration
+	path     string
+
+	mu       sync.RWMutex
+	snapshot []byte
+}
+
+// NewServer creates a Server sampling at the given interval
+func NewServer(interval time.Duration) *Server {
+	return &Server{
+		interval: interval,
+		path:     SocketPath(),
+	}
+}
+
+// Run listens on the socket and samples metrics until ctx is cancelled
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = listener.Close()
+		_ = os.Remove(s.path)
+	}()
+
+	log.Printf("Serving metrics on %s every %s", s.path, s.interval)
+
+	// fdinfo deltas span the whole interval once the first sample is taken
+	gpu.SetEngineSampling(true)
+
+	// Take a first sample before accepting clients so they never get an empty reply
+	s.sample()
+
+	go s.serve(listener)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.sample()
+		}
+	}
+}
+
+// listen creates the Unix socket, replacing a stale one left by a dead daemon
+func (s *Server) listen() (net.Listener, error) {
+	if conn, err := net.DialTimeout("unix", s.path, dialTimeout); err == nil {
+		_ = conn.Close()
+		return nil, errors.New("daemon already running on " + s.path)
+	}
+	_ = os.Remove(s.path)
+
+	listener, err := net.Listen("unix", s.path)
+	if err != nil {
+		return nil, errors.New("failed to listen on " + s.path + ": " + err.Error())
+	}
+
+	if err := os.Chmod(s.path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, errors.New("failed to restrict socket permissions: " + err.Error())
+	}
+
+	return listener, nil
+}
+
+// sample collects all metrics and stores the encoded snapshot
+func (s *Server) sample() {
+	snapshot := Snapshot{
+		Timestamp: time.Now(),
+		Interval:  s.interval,
+	}
+
+	if metrics, err := cpu.GetAllMetrics(); err == nil {
+		snapshot.CPU = metrics
+	}
+
+	if metrics, err := gpu.GetAllDevicesMetrics(); err == nil {
+		snapshot.GPUs = metrics
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Printf("Warning: failed to encode snapshot: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	s.snapshot = data
+	s.mu.Unlock()
+}
+
+// serve writes the latest snapshot to every client and closes the connection
+func (s *Server) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.RLock()
+		data := s.snapshot
+		s.mu.RUnlock()
+
+		_ = conn.SetWriteDeadline(time.Now().Add(time.Second))
+		_, _ = conn.Write(data)
+		_ = conn.Close()
+	}
+}
+
+// Fetch reads the latest snapshot from a running daemon
+func Fetch() (*Snapshot, error) {
+	conn, err := net.DialTimeout("unix", SocketPath(), dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = conn.Close() }()
+
+	if err := conn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(conn).Decode(&snapshot); err != nil {
+		return nil, errors.New("invalid daemon response: " + err.Error())
+	}
+
+	if snapshot.Stale() {
+		return nil, errors.New("daemon snapshot is stale")
+	}
+
+	return &snapshot, nil
+}
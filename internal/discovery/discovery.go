@@ -9,22 +9,35 @@ import (
 	"time"
 )
 
-// GPUPaths contains all discovered GPU-related paths
+// GPUPaths contains all discovered paths and identifiers for one GPU
 type GPUPaths struct {
-	Card   string `json:"card"`
-	HwMon  string `json:"hwmon"`
-	Device string `json:"device"`
+	Card       string `json:"card"`
+	HwMon      string `json:"hwmon"`
+	Device     string `json:"device"`
+	Index      int    `json:"index"`
+	PCI        string `json:"pci"`
+	Name       string `json:"name"`
+	Integrated bool   `json:"integrated"`
+}
+
+// PolicyPaths describes one cpufreq policy and the CPUs it drives
+type PolicyPaths struct {
+	Index int    `json:"index"`
+	Path  string `json:"path"`
+	CPUs  []int  `json:"cpus"`
 }
 
 // CPUPaths contains all discovered CPU-related paths
 type CPUPaths struct {
-	HwMon           string `json:"hwmon"`
-	SensorType      string `json:"sensor_type"`
-	CPUFreqBase     string `json:"cpufreq_base"`
-	BoostPath       string `json:"boost_path"`
-	CoreCount       int    `json:"core_count"`
-	AMDPstateBase   string `json:"amd_pstate_base"`
-	AMDPstatePerCPU string `json:"amd_pstate_per_cpu"`
+	HwMon           string          `json:"hwmon"`
+	SensorType      string          `json:"sensor_type"`
+	CPUFreqBase     string          `json:"cpufreq_base"`
+	BoostPath       string          `json:"boost_path"`
+	CoreCount       int             `json:"core_count"`
+	AMDPstateBase   string          `json:"amd_pstate_base"`
+	AMDPstatePerCPU string          `json:"amd_pstate_per_cpu"`
+	Policies        []*PolicyPaths  `json:"policies"`
+	Topology        []*CoreTopology `json:"topology"`
 }
 
 // PowerPaths contains power-related paths
@@ -42,16 +55,19 @@ type SystemInfo struct {
 
 // PathCache represents the complete cached path information
 type PathCache struct {
-	Version   string     `json:"version"`
-	Timestamp time.Time  `json:"timestamp"`
-	System    SystemInfo `json:"system"`
-	GPU       *GPUPaths  `json:"gpu"`
-	CPU       *CPUPaths  `json:"cpu"`
+	Version   string      `json:"version"`
+	Timestamp time.Time   `json:"timestamp"`
+	System    SystemInfo  `json:"system"`
+	GPU       []*GPUPaths `json:"gpu"`
+	CPU       *CPUPaths   `json:"cpu"`
 	Power     *PowerPaths `json:"power"`
-	
+
 	cacheFile string
 }
 
+// cacheVersion is bumped whenever the cache layout changes so old caches get rescanned
+const cacheVersion = "2.4"
+
 // NewPathCache creates a new PathCache instance
 func NewPathCache() (*PathCache, error) {
 	cacheDir, err := getCacheDir()
@@ -60,9 +76,9 @@ func NewPathCache() (*PathCache, error) {
 	}
 
 	cacheFile := filepath.Join(cacheDir, "paths.json")
-	
+
 	cache := &PathCache{
-		Version:   "1.0",
+		Version:   cacheVersion,
 		Timestamp: time.Now(),
 		cacheFile: cacheFile,
 	}
@@ -94,7 +110,7 @@ func getCacheDir() (string, error) {
 	}
 
 	cacheDir := filepath.Join(cacheHome, "waybar-amd-module")
-	
+
 	// Create directory if it doesn't exist
 	if err := os.MkdirAll(cacheDir, 0755); err != nil {
 		return "", err
@@ -114,6 +130,11 @@ func (c *PathCache) Load() error {
 		return err
 	}
 
+	if c.Version != cacheVersion {
+		c.Version = cacheVersion
+		return errors.New("cache version is outdated")
+	}
+
 	// Validate that cached paths still exist
 	if !c.Validate() {
 		return errors.New("cached paths are no longer valid")
@@ -125,7 +146,7 @@ func (c *PathCache) Load() error {
 // Save writes the cache to the filesystem
 func (c *PathCache) Save() error {
 	c.Timestamp = time.Now()
-	
+
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return err
@@ -137,4 +158,4 @@ func (c *PathCache) Save() error {
 // GetCacheFile returns the path to the cache file
 func (c *PathCache) GetCacheFile() string {
 	return c.cacheFile
-}
\ No newline at end of file
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
 )
 
@@ -15,6 +17,7 @@ func (c *PathCache) Scan() error {
 	c.System = SystemInfo{
 		Kernel: getKernelVersion(),
 	}
+	c.CPU, c.GPU, c.Power = nil, nil, nil
 
 	// Scan for AMD CPU
 	if cpuPaths, err := c.scanCPU(); err == nil {
@@ -22,10 +25,10 @@ func (c *PathCache) Scan() error {
 		c.System.AMDCpu = true
 	}
 
-	// Scan for AMD GPU
-	if gpuPaths, err := c.scanGPU(); err == nil {
-		c.GPU = gpuPaths
-		c.System.AMDGpuCount = 1
+	// Scan for AMD GPUs
+	if gpus, err := c.scanGPU(); err == nil {
+		c.GPU = gpus
+		c.System.AMDGpuCount = len(gpus)
 	}
 
 	// Scan for power paths
@@ -34,36 +37,43 @@ func (c *PathCache) Scan() error {
 	}
 
 	// Ensure we found at least something
-	if c.CPU == nil && c.GPU == nil {
+	if c.CPU == nil && len(c.GPU) == 0 {
 		return errors.New("no AMD hardware detected")
 	}
 
 	return nil
 }
 
-// scanGPU discovers AMD GPU paths using multiple methods
-func (c *PathCache) scanGPU() (*GPUPaths, error) {
+// scanGPU discovers every AMD GPU using multiple methods
+func (c *PathCache) scanGPU() ([]*GPUPaths, error) {
 	// Method 1: Scan DRM cards
-	if gpu, err := c.scanDRMCards(); err == nil {
-		return gpu, nil
+	if gpus, err := c.scanDRMCards(); err == nil {
+		return gpus, nil
 	}
 
 	// Method 2: Scan PCI drivers directly
-	if gpu, err := c.scanPCIDrivers(); err == nil {
-		return gpu, nil
+	if gpus, err := c.scanPCIDrivers(); err == nil {
+		return gpus, nil
 	}
 
 	return nil, errors.New("no AMD GPU found")
 }
 
 // scanDRMCards scans /sys/class/drm/card* for AMD GPUs
-func (c *PathCache) scanDRMCards() (*GPUPaths, error) {
+func (c *PathCache) scanDRMCards() ([]*GPUPaths, error) {
 	cardDirs, err := filepath.Glob("/sys/class/drm/card*")
 	if err != nil {
 		return nil, err
 	}
 
+	var gpus []*GPUPaths
 	for _, cardDir := range cardDirs {
+		// Skip connectors such as card1-DP-1
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(cardDir), "card"))
+		if err != nil {
+			continue
+		}
+
 		driverPath := filepath.Join(cardDir, "device", "driver")
 		if target, err := os.Readlink(driverPath); err == nil {
 			if strings.Contains(target, "amdgpu") {
@@ -74,52 +84,157 @@ func (c *PathCache) scanDRMCards() (*GPUPaths, error) {
 						Card:   cardDir,
 						HwMon:  hwmonDirs[0],
 						Device: filepath.Join(cardDir, "device"),
+						Index:  index,
 					}
 
 					// Validate essential files exist
 					if c.validateGPUPaths(gpu) {
-						return gpu, nil
+						identifyGPU(gpu)
+						gpus = append(gpus, gpu)
 					}
 				}
 			}
 		}
 	}
 
-	return nil, errors.New("no valid AMD GPU found in DRM cards")
+	if len(gpus) == 0 {
+		return nil, errors.New("no valid AMD GPU found in DRM cards")
+	}
+
+	sortGPUs(gpus)
+	return gpus, nil
 }
 
 // scanPCIDrivers scans PCI bus for AMD GPU drivers
-func (c *PathCache) scanPCIDrivers() (*GPUPaths, error) {
+func (c *PathCache) scanPCIDrivers() ([]*GPUPaths, error) {
 	pciDirs, err := filepath.Glob("/sys/bus/pci/drivers/amdgpu/*/hwmon/hwmon*")
 	if err != nil || len(pciDirs) == 0 {
 		return nil, errors.New("no AMD GPU found in PCI drivers")
 	}
 
-	hwmonPath := pciDirs[0]
-	devicePath := filepath.Dir(filepath.Dir(hwmonPath))
+	cardDirs, _ := filepath.Glob("/sys/class/drm/card*")
+
+	var gpus []*GPUPaths
+	for _, hwmonPath := range pciDirs {
+		devicePath := filepath.Dir(filepath.Dir(hwmonPath))
 
-	// Find corresponding card
-	cardPath := ""
-	if cardDirs, err := filepath.Glob("/sys/class/drm/card*"); err == nil {
+		// Find corresponding card
+		cardPath := ""
+		index := -1
 		for _, card := range cardDirs {
-			if cardDevice := filepath.Join(card, "device"); cardDevice == devicePath {
-				cardPath = card
-				break
+			cardDevice, err := filepath.EvalSymlinks(filepath.Join(card, "device"))
+			if err != nil {
+				continue
+			}
+			if resolved, err := filepath.EvalSymlinks(devicePath); err == nil && cardDevice == resolved {
+				if n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(card), "card")); err == nil {
+					cardPath = card
+					index = n
+					break
+				}
 			}
 		}
+
+		gpu := &GPUPaths{
+			Card:   cardPath,
+			HwMon:  hwmonPath,
+			Device: devicePath,
+			Index:  index,
+		}
+
+		if c.validateGPUPaths(gpu) {
+			identifyGPU(gpu)
+			gpus = append(gpus, gpu)
+		}
 	}
 
-	gpu := &GPUPaths{
-		Card:   cardPath,
-		HwMon:  hwmonPath,
-		Device: devicePath,
+	if len(gpus) == 0 {
+		return nil, errors.New("found AMD GPU but validation failed")
 	}
 
-	if c.validateGPUPaths(gpu) {
-		return gpu, nil
+	sortGPUs(gpus)
+	return gpus, nil
+}
+
+// identifyGPU fills the stable identifiers of a GPU: PCI slot, marketing name
+// and whether it is an APU's integrated graphics
+func identifyGPU(gpu *GPUPaths) {
+	if resolved, err := filepath.EvalSymlinks(gpu.Device); err == nil {
+		gpu.PCI = filepath.Base(resolved)
 	}
 
-	return nil, errors.New("found AMD GPU but validation failed")
+	// product_name is only provided by boards with a populated FRU EEPROM
+	if data, err := os.ReadFile(filepath.Join(gpu.Device, "product_name")); err == nil {
+		gpu.Name = strings.TrimSpace(string(data))
+	}
+	if gpu.Name == "" {
+		vendor, _ := os.ReadFile(filepath.Join(gpu.Device, "vendor"))
+		device, _ := os.ReadFile(filepath.Join(gpu.Device, "device"))
+		gpu.Name = "AMD GPU " + strings.TrimPrefix(strings.TrimSpace(string(vendor)), "0x") + ":" +
+			strings.TrimPrefix(strings.TrimSpace(string(device)), "0x")
+	}
+
+	gpu.Integrated = isIntegrated(gpu.Device)
+}
+
+// maxCarveOut is the largest VRAM an APU is assumed to reserve from system
+// memory when gpu_metrics cannot tell
+const maxCarveOut = 512 << 20
+
+// isIntegrated guesses whether the GPU is an APU's integrated graphics. The
+// driver exposes no flag for it, so in order of reliability:
+//   - the gpu_metrics format revision: 1 on dGPUs, 2 and 3 on APUs, only
+//     read while the device is active as it could wake a suspended dGPU
+//   - a VRAM carve-out of at most maxCarveOut next to a GTT pool
+//   - a missing mem_info_vram_vendor, which only dGPUs publish
+func isIntegrated(device string) bool {
+	if !runtimeActive(device) {
+		return integratedFromMemory(device)
+	}
+	if header, err := os.ReadFile(filepath.Join(device, "gpu_metrics")); err == nil && len(header) >= 4 {
+		switch header[2] {
+		case 1:
+			return false
+		case 2, 3:
+			return true
+		}
+	}
+
+	return integratedFromMemory(device)
+}
+
+// integratedFromMemory guesses whether the GPU is integrated from its memory
+// pools, which the driver reports without waking the device
+func integratedFromMemory(device string) bool {
+	vram, err := readSize(filepath.Join(device, "mem_info_vram_total"))
+	if err == nil && vram > 0 && pathExists(filepath.Join(device, "mem_info_gtt_total")) {
+		return vram <= maxCarveOut
+	}
+
+	return !pathExists(filepath.Join(device, "mem_info_vram_vendor"))
+}
+
+// runtimeActive reports whether power/runtime_status is active. Reading it is
+// handled by the PCI core and does not wake the device.
+func runtimeActive(device string) bool {
+	data, err := os.ReadFile(filepath.Join(device, "power", "runtime_status")) // #nosec G304 - path is from the amdgpu sysfs tree
+	return err == nil && strings.TrimSpace(string(data)) == "active"
+}
+
+// readSize reads a byte count from a sysfs file
+func readSize(path string) (uint64, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is from the amdgpu sysfs tree
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// sortGPUs orders GPUs by card index so list positions stay stable across scans
+func sortGPUs(gpus []*GPUPaths) {
+	sort.SliceStable(gpus, func(i, j int) bool {
+		return gpus[i].Index < gpus[j].Index
+	})
 }
 
 // validateGPUPaths checks if essential GPU metric files exist
@@ -178,8 +293,9 @@ func (c *PathCache) scanCPU() (*CPUPaths, error) {
 	// Find boost path
 	boostPath := c.findBoostPath()
 
-	// Find AMD pstate paths
-	amdPstateBase, amdPstatePerCPU := c.findAMDPstatePaths()
+	// Find cpufreq policies and AMD pstate paths
+	policies := c.findPolicies()
+	amdPstateBase, amdPstatePerCPU := c.findAMDPstatePaths(policies)
 
 	cpu := &CPUPaths{
 		HwMon:           hwmonPath,
@@ -189,7 +305,9 @@ func (c *PathCache) scanCPU() (*CPUPaths, error) {
 		CoreCount:       runtime.NumCPU(),
 		AMDPstateBase:   amdPstateBase,
 		AMDPstatePerCPU: amdPstatePerCPU,
+		Policies:        policies,
 	}
+	cpu.Topology = c.findTopology(cpu.CPUFreqBase)
 
 	return cpu, nil
 }
@@ -253,21 +371,56 @@ func (c *PathCache) findBoostPath() string {
 }
 
 // findAMDPstatePaths finds AMD pstate paths
-func (c *PathCache) findAMDPstatePaths() (string, string) {
+func (c *PathCache) findAMDPstatePaths(policies []*PolicyPaths) (string, string) {
 	amdPstateBase := "/sys/devices/system/cpu/amd_pstate"
-	amdPstatePerCPU := "/sys/devices/system/cpu/cpu0/cpufreq"
 
 	// Check if AMD pstate directory exists and has status file
-	if _, err := os.Stat(filepath.Join(amdPstateBase, "status")); err == nil {
-		// Verify per-CPU pstate files exist
-		if _, err := os.Stat(filepath.Join(amdPstatePerCPU, "energy_performance_preference")); err == nil {
-			return amdPstateBase, amdPstatePerCPU
+	if _, err := os.Stat(filepath.Join(amdPstateBase, "status")); err != nil {
+		return "", ""
+	}
+
+	// The first policy with per-CPU pstate files serves the single-value readers
+	for _, policy := range policies {
+		if _, err := os.Stat(filepath.Join(policy.Path, "energy_performance_preference")); err == nil {
+			return amdPstateBase, policy.Path
 		}
 	}
 
 	return "", ""
 }
 
+// findPolicies lists every cpufreq policy, ordered by policy number
+func (c *PathCache) findPolicies() []*PolicyPaths {
+	dirs, err := filepath.Glob("/sys/devices/system/cpu/cpufreq/policy[0-9]*")
+	if err != nil {
+		return nil
+	}
+
+	policies := make([]*PolicyPaths, 0, len(dirs))
+	for _, dir := range dirs {
+		index, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "policy"))
+		if err != nil {
+			continue
+		}
+
+		policy := &PolicyPaths{Index: index, Path: dir}
+		if data, err := os.ReadFile(filepath.Join(dir, "affected_cpus")); err == nil {
+			for _, field := range strings.Fields(string(data)) {
+				if cpu, err := strconv.Atoi(field); err == nil {
+					policy.CPUs = append(policy.CPUs, cpu)
+				}
+			}
+		}
+		policies = append(policies, policy)
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Index < policies[j].Index
+	})
+
+	return policies
+}
+
 // scanPower discovers power-related paths
 func (c *PathCache) scanPower() (*PowerPaths, error) {
 	power := &PowerPaths{}
@@ -0,0 +1,207 @@
+// Package discovery provides CPU topology discovery for AMD CPUs
+package discovery
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CoreTopology describes where one logical CPU sits in the package.
+// CCD and CCX are numbered from 1 within the whole system. k10temp numbers
+// its Tccd labels by physical slot instead, so they differ on parts with
+// fused-off CCDs and on the second socket.
+type CoreTopology struct {
+	CPU      int   `json:"cpu"`
+	Package  int   `json:"package"`
+	Die      int   `json:"die"`
+	Cluster  int   `json:"cluster"`
+	Core     int   `json:"core"`
+	Siblings []int `json:"siblings"`
+	L3       []int `json:"l3"`
+	L3Size   int   `json:"l3_size"` // KiB
+	CCD      int   `json:"ccd"`
+	CCX      int   `json:"ccx"`
+}
+
+// zen2Family is the cpu family of Zen, Zen+ and Zen 2, which put two CCXs on
+// every CCD
+const zen2Family = 0x17
+
+// findTopology reads the topology and L3 cache of every online CPU under
+// base and assigns CCD and CCX numbers
+func (c *PathCache) findTopology(base string) []*CoreTopology {
+	dirs, err := filepath.Glob(filepath.Join(base, "cpu[0-9]*", "topology"))
+	if err != nil {
+		return nil
+	}
+
+	cores := make([]*CoreTopology, 0, len(dirs))
+	for _, dir := range dirs {
+		id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(filepath.Dir(dir)), "cpu"))
+		if err != nil {
+			continue
+		}
+
+		cache := filepath.Join(filepath.Dir(dir), "cache", "index3")
+		cores = append(cores, &CoreTopology{
+			CPU:      id,
+			Package:  readTopologyInt(filepath.Join(dir, "physical_package_id")),
+			Die:      readTopologyInt(filepath.Join(dir, "die_id")),
+			Cluster:  readTopologyInt(filepath.Join(dir, "cluster_id")),
+			Core:     readTopologyInt(filepath.Join(dir, "core_id")),
+			Siblings: readCPUList(filepath.Join(dir, "thread_siblings_list")),
+			L3:       readCPUList(filepath.Join(cache, "shared_cpu_list")),
+			L3Size:   readCacheSize(filepath.Join(cache, "size")),
+		})
+	}
+
+	sort.Slice(cores, func(i, j int) bool {
+		return cores[i].CPU < cores[j].CPU
+	})
+
+	ccxPerCCD := 1
+	if readCPUFamily() == zen2Family {
+		ccxPerCCD = 2
+	}
+	assignComplexes(cores, ccxPerCCD)
+	return cores
+}
+
+// assignComplexes numbers CCXs by L3 cache and CCDs by die. Most parts report
+// one die per package, so CCDs are then derived from the CCXs: Zen 3 and
+// later have one CCX per CCD, Zen to Zen 2 two consecutive ones. A Zen 2 CCD
+// with a whole CCX fused off would be paired with the next CCD's CCX.
+func assignComplexes(cores []*CoreTopology, ccxPerCCD int) {
+	dies := make(map[int]map[int]bool)
+	for _, core := range cores {
+		if dies[core.Package] == nil {
+			dies[core.Package] = make(map[int]bool)
+		}
+		dies[core.Package][core.Die] = true
+	}
+
+	ccxs := make(map[string]int)
+	ccds := make(map[string]int)
+	// Index of each CCX within its package, to pair them into CCDs
+	packageCCXs := make(map[int]int)
+	ccxIndex := make(map[string]int)
+	for _, core := range cores {
+		// Cores are sorted, so numbering follows the first CPU of each complex
+		l3 := strconv.Itoa(core.Package) + ":" + formatCPUList(core.L3)
+		if len(core.L3) == 0 {
+			l3 = strconv.Itoa(core.Package) + ":" + strconv.Itoa(core.Die)
+		}
+		if _, seen := ccxs[l3]; !seen {
+			ccxs[l3] = len(ccxs) + 1
+			ccxIndex[l3] = packageCCXs[core.Package]
+			packageCCXs[core.Package]++
+		}
+		core.CCX = ccxs[l3]
+
+		ccd := strconv.Itoa(core.Package) + ":" + strconv.Itoa(ccxIndex[l3]/ccxPerCCD)
+		if len(dies[core.Package]) > 1 {
+			ccd = strconv.Itoa(core.Package) + ":" + strconv.Itoa(core.Die)
+		}
+		if _, seen := ccds[ccd]; !seen {
+			ccds[ccd] = len(ccds) + 1
+		}
+		core.CCD = ccds[ccd]
+	}
+}
+
+// readCPUFamily returns the cpu family of /proc/cpuinfo, or -1 when unknown
+func readCPUFamily() int {
+	file, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return -1
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if found && strings.TrimSpace(key) == "cpu family" {
+			family, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return -1
+			}
+			return family
+		}
+	}
+	return -1
+}
+
+// readTopologyInt reads a topology id, or -1 when it is missing
+func readTopologyInt(path string) int {
+	data, err := os.ReadFile(path) // #nosec G304 - path is from the cpu sysfs tree
+	if err != nil {
+		return -1
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// readCacheSize reads a cache size such as "32768K" in KiB
+func readCacheSize(path string) int {
+	data, err := os.ReadFile(path) // #nosec G304 - path is from the cpu sysfs tree
+	if err != nil {
+		return 0
+	}
+	text := strings.TrimSpace(string(data))
+	multiplier := 1
+	switch {
+	case strings.HasSuffix(text, "K"):
+		text = strings.TrimSuffix(text, "K")
+	case strings.HasSuffix(text, "M"):
+		text, multiplier = strings.TrimSuffix(text, "M"), 1024
+	}
+	size, err := strconv.Atoi(text)
+	if err != nil {
+		return 0
+	}
+	return size * multiplier
+}
+
+// readCPUList reads a sysfs CPU list such as "0-3,8-11"
+func readCPUList(path string) []int {
+	data, err := os.ReadFile(path) // #nosec G304 - path is from the cpu sysfs tree
+	if err != nil {
+		return nil
+	}
+
+	var cpus []int
+	for _, part := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil {
+				continue
+			}
+		}
+		for id := start; id <= end; id++ {
+			cpus = append(cpus, id)
+		}
+	}
+	sort.Ints(cpus)
+	return cpus
+}
+
+// formatCPUList turns a CPU list into a map key
+func formatCPUList(cpus []int) string {
+	parts := make([]string, 0, len(cpus))
+	for _, id := range cpus {
+		parts = append(parts, strconv.Itoa(id))
+	}
+	return strings.Join(parts, ",")
+}
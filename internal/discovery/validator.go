@@ -4,12 +4,13 @@ package discovery
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 // Validate checks if all cached paths still exist and are accessible
 func (c *PathCache) Validate() bool {
-	if c.GPU != nil && !c.validateGPU() {
+	if len(c.GPU) > 0 && !c.validateGPU() {
 		return false
 	}
 
@@ -24,27 +25,43 @@ func (c *PathCache) Validate() bool {
 	return true
 }
 
-// validateGPU checks if GPU paths are valid
+// validateGPU checks if the paths of every GPU are still valid
 func (c *PathCache) validateGPU() bool {
-	if c.GPU == nil {
-		return true
+	for _, gpu := range c.GPU {
+		if !validateGPUDevice(gpu) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validateGPUDevice checks if the paths of a single GPU are valid
+func validateGPUDevice(gpu *GPUPaths) bool {
+	if gpu == nil {
+		return false
 	}
 
 	// Check hwmon path exists
-	if c.GPU.HwMon != "" {
-		if !pathExists(c.GPU.HwMon) {
+	if gpu.HwMon != "" {
+		if !pathExists(gpu.HwMon) {
+			return false
+		}
+
+		// APUs often expose only power1_average
+		if !pathExists(filepath.Join(gpu.HwMon, "power1_input")) &&
+			!pathExists(filepath.Join(gpu.HwMon, "power1_average")) {
 			return false
 		}
 
 		// Check essential metric files
 		essentialFiles := []string{
-			"power1_input",
 			"temp1_input",
 			"freq1_input",
 		}
 
 		for _, file := range essentialFiles {
-			path := filepath.Join(c.GPU.HwMon, file)
+			path := filepath.Join(gpu.HwMon, file)
 			if !pathExists(path) {
 				return false
 			}
@@ -52,12 +69,12 @@ func (c *PathCache) validateGPU() bool {
 	}
 
 	// Check device path exists
-	if c.GPU.Device != "" && !pathExists(c.GPU.Device) {
+	if gpu.Device != "" && !pathExists(gpu.Device) {
 		return false
 	}
 
 	// Check card path exists
-	if c.GPU.Card != "" && !pathExists(c.GPU.Card) {
+	if gpu.Card != "" && !pathExists(gpu.Card) {
 		return false
 	}
 
@@ -96,6 +113,20 @@ func (c *PathCache) validateCPU() bool {
 		}
 	}
 
+	// Policies disappear when CPUs are taken offline, rescan then
+	for _, policy := range c.CPU.Policies {
+		if !pathExists(policy.Path) {
+			return false
+		}
+	}
+
+	// Offline CPUs lose their topology directory
+	for _, core := range c.CPU.Topology {
+		if !pathExists(filepath.Join(c.CPU.CPUFreqBase, "cpu"+strconv.Itoa(core.CPU), "topology")) {
+			return false
+		}
+	}
+
 	// Boost path is optional, so don't fail validation if it doesn't exist
 
 	return true
@@ -0,0 +1,209 @@
+// Package formatting derives Waybar state classes from metric thresholds
+package formatting
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+)
+
+// State classes emitted alongside the base class
+const (
+	ClassWarning  = "warning"
+	ClassCritical = "critical"
+)
+
+// DefaultHysteresisRatio is the hysteresis of a level, as a fraction of the
+// level, when neither --hysteresis nor the config file set one: metrics are
+// in °C, %, GHz, W or load, so no single absolute margin fits them all
+const DefaultHysteresisRatio = 0.03
+
+// ParseThresholds applies --warn, --crit and --hysteresis values such as
+// "temp=80" on top of the thresholds from the config file. Only the given
+// metrics are allowed.
+func ParseThresholds(limits map[string]config.Threshold, warn, crit, hysteresis []string, metrics []string) (map[string]config.Threshold, error) {
+	merged := make(map[string]config.Threshold, len(limits))
+	for metric, limit := range limits {
+		if !slices.Contains(metrics, metric) {
+			return nil, errors.New("unknown threshold metric: " + metric + " (must be one of " + strings.Join(metrics, ", ") + ")")
+		}
+		merged[metric] = limit
+	}
+
+	apply := func(values []string, set func(*config.Threshold, float64)) error {
+		for _, value := range values {
+			metric, number, found := strings.Cut(value, "=")
+			if !found {
+				return errors.New("invalid threshold: " + value + " (expected metric=value)")
+			}
+			if !slices.Contains(metrics, metric) {
+				return errors.New("unknown threshold metric: " + metric + " (must be one of " + strings.Join(metrics, ", ") + ")")
+			}
+			level, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return errors.New("invalid threshold value: " + value)
+			}
+			limit := merged[metric]
+			set(&limit, level)
+			merged[metric] = limit
+		}
+		return nil
+	}
+
+	if err := apply(warn, func(t *config.Threshold, v float64) { t.Warning = v }); err != nil {
+		return nil, err
+	}
+	if err := apply(crit, func(t *config.Threshold, v float64) { t.Critical = v }); err != nil {
+		return nil, err
+	}
+	if err := apply(hysteresis, func(t *config.Threshold, v float64) { t.Hysteresis = &v }); err != nil {
+		return nil, err
+	}
+
+	for metric, limit := range merged {
+		if limit.Hysteresis != nil && *limit.Hysteresis < 0 {
+			return nil, errors.New("hysteresis of " + metric + " must not be negative")
+		}
+	}
+	return merged, nil
+}
+
+// ThresholdClasses returns the warning/critical classes for the given values:
+// the worst level overall plus one "<metric>-<level>" class per metric. A
+// level is kept until the value falls the hysteresis of its metric below the
+// threshold, using the previous levels of scope saved in the runtime directory.
+func ThresholdClasses(scope string, values map[string]float64, limits map[string]config.Threshold) []string {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	var classes []string
+	worst := ""
+	updateLevels(scope, func(state map[string]string) {
+		classes, worst = thresholdClasses(state, values, limits)
+	})
+
+	if worst == "" {
+		return nil
+	}
+	return append([]string{worst}, classes...)
+}
+
+// thresholdClasses computes the per-metric classes and the worst level,
+// updating the saved levels in state
+func thresholdClasses(state map[string]string, values map[string]float64, limits map[string]config.Threshold) ([]string, string) {
+	var classes []string
+	worst := ""
+
+	metrics := make([]string, 0, len(values))
+	for metric := range values {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+
+	for _, metric := range metrics {
+		limit, ok := limits[metric]
+		if !ok {
+			continue
+		}
+
+		// Modules with different thresholds for a metric keep separate levels
+		key := levelKey(metric, limit)
+		level := thresholdLevel(values[metric], limit, state[key])
+		if level == "" {
+			delete(state, key)
+			continue
+		}
+		state[key] = level
+
+		classes = append(classes, metric+"-"+level)
+		if worst != ClassCritical {
+			worst = level
+		}
+	}
+	return classes, worst
+}
+
+// levelKey identifies the saved level of a metric under given thresholds
+func levelKey(metric string, limit config.Threshold) string {
+	key := metric + "@" + strconv.FormatFloat(limit.Warning, 'g', -1, 64) + "/" + strconv.FormatFloat(limit.Critical, 'g', -1, 64)
+	if limit.Hysteresis != nil {
+		key += "/" + strconv.FormatFloat(*limit.Hysteresis, 'g', -1, 64)
+	}
+	return key
+}
+
+// thresholdLevel returns the level of value, keeping the previous level
+// while the value stays within the hysteresis of its threshold
+func thresholdLevel(value float64, limit config.Threshold, previous string) string {
+	switch {
+	case limit.Critical > 0 && (value >= limit.Critical || previous == ClassCritical && value >= limit.Critical-hysteresisOf(limit, limit.Critical)):
+		return ClassCritical
+	case limit.Warning > 0 && (value >= limit.Warning || previous != "" && value >= limit.Warning-hysteresisOf(limit, limit.Warning)):
+		return ClassWarning
+	default:
+		return ""
+	}
+}
+
+// hysteresisOf returns the hysteresis of a level: the configured one, else
+// DefaultHysteresisRatio of the level
+func hysteresisOf(limit config.Threshold, level float64) float64 {
+	if limit.Hysteresis != nil {
+		return *limit.Hysteresis
+	}
+	return level * DefaultHysteresisRatio
+}
+
+// levelsPath returns the file keeping the levels of scope between invocations
+func levelsPath(scope string) string {
+	name := strings.NewReplacer("/", "_", ":", "_").Replace(scope)
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir == "" {
+		return filepath.Join(os.TempDir(), "waybar-amd-module-"+strconv.Itoa(os.Getuid())+"-levels-"+name+".json")
+	}
+	return filepath.Join(runtimeDir, "waybar-amd-module-levels-"+name+".json")
+}
+
+// updateLevels loads the saved levels of scope, lets update modify them and
+// stores the result. Waybar starts modules concurrently, so the file is
+// locked for the whole read-modify-write. Errors are ignored: the call then
+// only loses the hysteresis.
+func updateLevels(scope string, update func(map[string]string)) {
+	state := make(map[string]string)
+
+	file, err := os.OpenFile(levelsPath(scope), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		update(state)
+		return
+	}
+	defer func() { _ = file.Close() }()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err == nil {
+		defer func() { _ = syscall.Flock(int(file.Fd()), syscall.LOCK_UN) }()
+	}
+
+	if data, err := io.ReadAll(file); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+
+	update(state)
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := file.Truncate(0); err != nil {
+		return
+	}
+	_, _ = file.WriteAt(data, 0)
+}
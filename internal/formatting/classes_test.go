@@ -0,0 +1,150 @@
+package formatting
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bnema/waybar-amd-module/internal/config"
+)
+
+func hysteresis(value float64) *float64 {
+	return &value
+}
+
+func TestParseThresholds(t *testing.T) {
+	metrics := []string{"temp", "usage"}
+	file := map[string]config.Threshold{"temp": {Warning: 70, Critical: 90}}
+
+	tests := []struct {
+		name       string
+		limits     map[string]config.Threshold
+		warn, crit []string
+		hysteresis []string
+		want       map[string]config.Threshold
+		wantErr    bool
+	}{
+		{
+			name:   "config file only",
+			limits: file,
+			want:   map[string]config.Threshold{"temp": {Warning: 70, Critical: 90}},
+		},
+		{
+			name:   "flag overrides one level",
+			limits: file,
+			warn:   []string{"temp=80"},
+			want:   map[string]config.Threshold{"temp": {Warning: 80, Critical: 90}},
+		},
+		{
+			name: "flags add a metric",
+			warn: []string{"usage=85"},
+			crit: []string{"usage=95.5"},
+			want: map[string]config.Threshold{"usage": {Warning: 85, Critical: 95.5}},
+		},
+		{
+			name:       "per metric hysteresis",
+			warn:       []string{"temp=80"},
+			hysteresis: []string{"temp=3"},
+			want:       map[string]config.Threshold{"temp": {Warning: 80, Hysteresis: hysteresis(3)}},
+		},
+		{name: "unknown flag metric", warn: []string{"fan=2000"}, wantErr: true},
+		{name: "unknown config metric", limits: map[string]config.Threshold{"fan": {Warning: 1}}, wantErr: true},
+		{name: "missing value", warn: []string{"temp"}, wantErr: true},
+		{name: "invalid value", crit: []string{"temp=hot"}, wantErr: true},
+		{name: "negative hysteresis", hysteresis: []string{"temp=-1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseThresholds(tt.limits, tt.warn, tt.crit, tt.hysteresis, metrics)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for metric, want := range tt.want {
+				limit := got[metric]
+				if limit.Warning != want.Warning || limit.Critical != want.Critical ||
+					(limit.Hysteresis == nil) != (want.Hysteresis == nil) ||
+					limit.Hysteresis != nil && *limit.Hysteresis != *want.Hysteresis {
+					t.Errorf("%s: got %+v, want %+v", metric, limit, want)
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdLevel(t *testing.T) {
+	limit := config.Threshold{Warning: 80, Critical: 90, Hysteresis: hysteresis(2)}
+
+	tests := []struct {
+		name     string
+		value    float64
+		limit    config.Threshold
+		previous string
+		want     string
+	}{
+		{name: "below warning", value: 79.9, limit: limit, want: ""},
+		{name: "enters warning on the threshold", value: 80, limit: limit, want: ClassWarning},
+		{name: "keeps warning within hysteresis", value: 78, limit: limit, previous: ClassWarning, want: ClassWarning},
+		{name: "leaves warning below hysteresis", value: 77.9, limit: limit, previous: ClassWarning, want: ""},
+		{name: "no hysteresis without a previous level", value: 79, limit: limit, want: ""},
+		{name: "enters critical on the threshold", value: 90, limit: limit, previous: ClassWarning, want: ClassCritical},
+		{name: "critical overrides warning", value: 95, limit: limit, want: ClassCritical},
+		{name: "keeps critical within hysteresis", value: 88, limit: limit, previous: ClassCritical, want: ClassCritical},
+		{name: "critical falls back to warning", value: 87.9, limit: limit, previous: ClassCritical, want: ClassWarning},
+		{name: "critical clears below warning hysteresis", value: 77, limit: limit, previous: ClassCritical, want: ""},
+		{name: "critical only", value: 91, limit: config.Threshold{Critical: 90}, want: ClassCritical},
+		{name: "warning only never goes critical", value: 200, limit: config.Threshold{Warning: 80}, want: ClassWarning},
+		{name: "relative default hysteresis keeps", value: 4.4, limit: config.Threshold{Warning: 4.5}, previous: ClassWarning, want: ClassWarning},
+		{name: "relative default hysteresis clears", value: 4.3, limit: config.Threshold{Warning: 4.5}, previous: ClassWarning, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := thresholdLevel(tt.value, tt.limit, tt.previous); got != tt.want {
+				t.Errorf("thresholdLevel(%v, %+v, %q) = %q, want %q", tt.value, tt.limit, tt.previous, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThresholdClasses(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+
+	limits := map[string]config.Threshold{
+		"temp":  {Warning: 80, Critical: 90, Hysteresis: hysteresis(2)},
+		"usage": {Warning: 50},
+	}
+
+	steps := []struct {
+		values map[string]float64
+		want   []string
+	}{
+		{map[string]float64{"temp": 70, "usage": 10}, nil},
+		{map[string]float64{"temp": 85, "usage": 10}, []string{ClassWarning, "temp-warning"}},
+		{map[string]float64{"temp": 91, "usage": 60}, []string{ClassCritical, "temp-critical", "usage-warning"}},
+		{map[string]float64{"temp": 89, "usage": 10}, []string{ClassCritical, "temp-critical"}},
+		{map[string]float64{"temp": 79, "usage": 10}, []string{ClassWarning, "temp-warning"}},
+		{map[string]float64{"temp": 77, "usage": 10}, nil},
+	}
+
+	for i, step := range steps {
+		if got := ThresholdClasses("cpu", step.values, limits); !slices.Equal(got, step.want) {
+			t.Errorf("step %d: got %v, want %v", i, got, step.want)
+		}
+	}
+
+	// Other thresholds for the same metric do not inherit the saved level
+	ThresholdClasses("cpu", map[string]float64{"temp": 85}, limits)
+	other := map[string]config.Threshold{"temp": {Warning: 84, Hysteresis: hysteresis(5)}}
+	if got := ThresholdClasses("cpu", map[string]float64{"temp": 82}, other); got != nil {
+		t.Errorf("other thresholds: got %v, want none", got)
+	}
+}
@@ -0,0 +1,162 @@
+// Package formatting renders Pango markup for Waybar text and tooltips
+package formatting
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultGradient runs from green through yellow to red
+var DefaultGradient = []string{"#50fa7b", "#f1fa8c", "#ff5555"}
+
+// DefaultTempRange is the temperature span of the gradient in °C
+var DefaultTempRange = [2]float64{40, 95}
+
+// valuePattern matches the temperatures and percentages colored in markup mode
+var valuePattern = regexp.MustCompile(`-?\d+(?:\.\d+)?(?:°C|%)`)
+
+// markupEscaper escapes the characters Pango treats as markup
+var markupEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Markup colors values on a gradient: temperatures across TempRange and
+// percentages across 0-100
+type Markup struct {
+	Gradient  [][3]float64
+	TempRange [2]float64
+}
+
+// markup is set by SetMarkup, nil leaves output as plain text
+var markup *Markup
+
+// SetMarkup enables Pango markup in FormatJSONOutput, nil disables it
+func SetMarkup(m *Markup) {
+	markup = m
+}
+
+// NewMarkup parses the gradient colors (#rrggbb) and checks the range
+func NewMarkup(colors []string, tempRange [2]float64) (*Markup, error) {
+	if len(colors) < 2 {
+		return nil, errors.New("gradient needs at least two colors")
+	}
+	if tempRange[1] <= tempRange[0] {
+		return nil, errors.New("temperature range must be increasing")
+	}
+
+	m := &Markup{TempRange: tempRange}
+	for _, color := range colors {
+		hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || len(hex) != 6 {
+			return nil, errors.New("invalid gradient color: " + color + " (expected #rrggbb)")
+		}
+		m.Gradient = append(m.Gradient, [3]float64{float64(value >> 16), float64(value >> 8 & 0xff), float64(value & 0xff)})
+	}
+	return m, nil
+}
+
+// Color returns the gradient color at fraction (0-1)
+func (m *Markup) Color(fraction float64) string {
+	fraction = min(max(fraction, 0), 1)
+	position := fraction * float64(len(m.Gradient)-1)
+	index := min(int(position), len(m.Gradient)-2)
+	mix := position - float64(index)
+
+	from, to := m.Gradient[index], m.Gradient[index+1]
+	var rgb [3]int
+	for i := range rgb {
+		rgb[i] = int(from[i] + (to[i]-from[i])*mix + 0.5)
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+// Text escapes text and colors its temperatures and percentages. Text
+// without such values is colored as a whole at percentage, when known.
+func (m *Markup) Text(text string, percentage float64) string {
+	if valuePattern.MatchString(text) {
+		return m.colorValues(text)
+	}
+	if percentage < 0 {
+		return EscapeMarkup(text)
+	}
+	return m.span(text, percentage/100)
+}
+
+// Tooltip escapes the tooltip, bolds section headers, aligns the values of
+// "label: value" lines in each block and colors temperatures and percentages.
+// The tooltip is set in a monospace font so the columns line up.
+func (m *Markup) Tooltip(tooltip string) string {
+	if tooltip == "" {
+		return ""
+	}
+
+	blocks := strings.Split(tooltip, "\n\n")
+	for i, block := range blocks {
+		lines := strings.Split(block, "\n")
+
+		width := 0
+		for _, line := range lines {
+			if label, _, ok := splitLabel(line); ok {
+				width = max(width, utf8.RuneCountInString(label))
+			}
+		}
+
+		for j, line := range lines {
+			label, value, ok := splitLabel(line)
+			switch {
+			case strings.HasSuffix(line, ":"):
+				lines[j] = "<b>" + EscapeMarkup(line) + "</b>"
+			case ok:
+				padding := strings.Repeat(" ", width-utf8.RuneCountInString(label))
+				lines[j] = EscapeMarkup(label) + padding + " " + m.colorValues(value)
+			default:
+				lines[j] = m.colorValues(line)
+			}
+		}
+		blocks[i] = strings.Join(lines, "\n")
+	}
+
+	return `<span font_family="monospace">` + strings.Join(blocks, "\n\n") + "</span>"
+}
+
+// colorValues escapes s and wraps every temperature and percentage in a span
+func (m *Markup) colorValues(s string) string {
+	var out strings.Builder
+	last := 0
+	for _, match := range valuePattern.FindAllStringIndex(s, -1) {
+		out.WriteString(EscapeMarkup(s[last:match[0]]))
+
+		value := s[match[0]:match[1]]
+		number, _ := strconv.ParseFloat(strings.TrimRight(value, "°C%"), 64)
+		fraction := number / 100
+		if strings.HasSuffix(value, "°C") {
+			fraction = (number - m.TempRange[0]) / (m.TempRange[1] - m.TempRange[0])
+		}
+		out.WriteString(m.span(value, fraction))
+		last = match[1]
+	}
+	out.WriteString(EscapeMarkup(s[last:]))
+	return out.String()
+}
+
+// span colors s at fraction of the gradient
+func (m *Markup) span(s string, fraction float64) string {
+	return `<span foreground="` + m.Color(fraction) + `">` + EscapeMarkup(s) + "</span>"
+}
+
+// splitLabel splits a "label: value" line, including the colon in label
+func splitLabel(line string) (string, string, bool) {
+	label, value, found := strings.Cut(line, ": ")
+	if !found || strings.HasPrefix(line, " ") {
+		return "", "", false
+	}
+	return label + ":", value, true
+}
+
+// EscapeMarkup escapes &, < and > so strings are safe inside Pango markup
+func EscapeMarkup(s string) string {
+	return markupEscaper.Replace(s)
+}
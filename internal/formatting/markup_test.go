@@ -0,0 +1,48 @@
+package formatting
+
+import "testing"
+
+func TestColor(t *testing.T) {
+	m, err := NewMarkup([]string{"#000000", "#ff8000", "#ffffff"}, DefaultTempRange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		fraction float64
+		want     string
+	}{
+		{-0.5, "#000000"},
+		{0, "#000000"},
+		{0.25, "#804000"},
+		{0.5, "#ff8000"},
+		{0.75, "#ffc080"},
+		{1, "#ffffff"},
+		{2, "#ffffff"},
+	}
+
+	for _, tt := range tests {
+		if got := m.Color(tt.fraction); got != tt.want {
+			t.Errorf("Color(%v) = %s, want %s", tt.fraction, got, tt.want)
+		}
+	}
+}
+
+func TestNewMarkupErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		colors    []string
+		tempRange [2]float64
+	}{
+		{"single color", []string{"#ffffff"}, DefaultTempRange},
+		{"short color", []string{"#fff", "#000000"}, DefaultTempRange},
+		{"not hex", []string{"#gggggg", "#000000"}, DefaultTempRange},
+		{"decreasing range", DefaultGradient, [2]float64{90, 40}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewMarkup(tt.colors, tt.tempRange); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
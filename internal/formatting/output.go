@@ -4,13 +4,16 @@ package formatting
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 )
 
 // WaybarOutput represents the JSON structure expected by Waybar
 type WaybarOutput struct {
-	Text    string `json:"text"`
-	Tooltip string `json:"tooltip"`
-	Class   string `json:"class"`
+	Text       string   `json:"text"`
+	Tooltip    string   `json:"tooltip,omitempty"`
+	Class      []string `json:"class"`
+	Percentage *int     `json:"percentage,omitempty"`
+	Alt        string   `json:"alt,omitempty"`
 }
 
 // ValidateNoTooltipFlag checks if --no-tooltip is used with text format
@@ -24,25 +27,27 @@ func ValidateNoTooltipFlag(noTooltipFlag bool, formatFlag string) bool {
 	}
 }
 
-// FormatJSONOutput formats output for JSON mode, handling --no-tooltip flag
-func FormatJSONOutput(text string, tooltip string, class string, noTooltipFlag bool) {
-	switch {
-	case noTooltipFlag:
-		// Simple JSON output without tooltip
-		output := map[string]any{
-			"text":  text,
-			"class": class,
-		}
-		jsonData, _ := json.Marshal(output)
-		fmt.Println(string(jsonData))
-	default:
-		// Standard Waybar output with tooltip
-		output := WaybarOutput{
-			Text:    text,
-			Tooltip: tooltip,
-			Class:   class,
-		}
-		jsonData, _ := json.Marshal(output)
-		fmt.Println(string(jsonData))
+// FormatJSONOutput formats output for JSON mode, handling --no-tooltip flag.
+// Waybar applies every entry of classes as a CSS class, uses percentage (0-100,
+// omitted when negative) for format-icons and states, and alt for format-icons maps.
+// Text and tooltip are turned into Pango markup when SetMarkup was called.
+func FormatJSONOutput(text string, tooltip string, classes []string, percentage float64, alt string, noTooltipFlag bool) {
+	if markup != nil {
+		text, tooltip = markup.Text(text, percentage), markup.Tooltip(tooltip)
+	}
+
+	output := WaybarOutput{
+		Text:  text,
+		Class: classes,
+		Alt:   alt,
 	}
-}
\ No newline at end of file
+	if !noTooltipFlag {
+		output.Tooltip = tooltip
+	}
+	if percentage >= 0 {
+		rounded := int(math.Round(percentage))
+		output.Percentage = &rounded
+	}
+	jsonData, _ := json.Marshal(output)
+	fmt.Println(string(jsonData))
+}
@@ -0,0 +1,198 @@
+// Package formatting renders user-defined text and tooltip templates
+package formatting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/bnema/waybar-amd-module/internal/nerdfonts"
+)
+
+// placeholderPattern matches {field} and {field:printf-verb} placeholders
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z0-9_.]+)(?::([^{}]+))?\}`)
+
+// Template is a user-defined output layout. It accepts Go text/template
+// syntax over the metrics struct ({{.Power}}) or {placeholder} syntax over
+// the JSON field names of the metrics ({power}, {vram.used}, {power:%.0f}).
+type Template struct {
+	tmpl *template.Template
+}
+
+// ParseTemplate parses a format string. Strings containing "{{" are Go
+// templates, anything else is placeholder style.
+func ParseTemplate(format string) (*Template, error) {
+	if !strings.Contains(format, "{{") {
+		format = placeholderPattern.ReplaceAllStringFunc(format, func(match string) string {
+			parts := placeholderPattern.FindStringSubmatch(match)
+			if parts[2] == "" {
+				return "{{field " + strconv.Quote(parts[1]) + "}}"
+			}
+			return "{{field " + strconv.Quote(parts[1]) + " " + strconv.Quote(parts[2]) + "}}"
+		})
+	}
+
+	tmpl, err := template.New("format").Option("missingkey=error").Funcs(templateFuncs(nil)).Parse(format)
+	if err != nil {
+		return nil, errors.New("invalid format: " + err.Error())
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Render executes the template over data, usually a *cpu.Metrics or *gpu.Metrics
+func (t *Template) Render(data any) (string, error) {
+	// Placeholders look fields up by their JSON names, which also flattens
+	// embedded structs the same way the daemon output does
+	var fields map[string]any
+	if encoded, err := json.Marshal(data); err == nil {
+		_ = json.Unmarshal(encoded, &fields)
+	}
+
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := tmpl.Funcs(templateFuncs(fields)).Execute(&out, data); err != nil {
+		return "", errors.New("failed to render format: " + err.Error())
+	}
+	return out.String(), nil
+}
+
+// templateFuncs returns the helper functions available in templates
+func templateFuncs(fields map[string]any) template.FuncMap {
+	return template.FuncMap{
+		// field looks up a JSON field such as "vram.used", with an optional printf verb
+		"field": func(name string, verb ...string) (string, error) {
+			value, err := lookupField(fields, name)
+			if err != nil {
+				return "", err
+			}
+			if len(verb) > 0 {
+				return fmt.Sprintf(verb[0], verbValue(verb[0], value)), nil
+			}
+			return formatValue(value), nil
+		},
+		// round formats a number with the given number of decimals
+		"round": func(decimals int, value any) string {
+			return strconv.FormatFloat(toFloat(value), 'f', decimals, 64)
+		},
+		// unit formats a number with one decimal followed by a unit, e.g. 12.3W
+		"unit": func(value any, unit string) string {
+			return strconv.FormatFloat(toFloat(value), 'f', 1, 64) + unit
+		},
+		// bytes formats a byte count with binary units
+		"bytes": func(value any) string {
+			return formatByteCount(toFloat(value))
+		},
+		// pad right-aligns a value to width characters
+		"pad": func(width int, value any) string {
+			return fmt.Sprintf("%*s", width, formatValue(value))
+		},
+		// padRight left-aligns a value to width characters
+		"padRight": func(width int, value any) string {
+			return fmt.Sprintf("%-*s", width, formatValue(value))
+		},
+		// icon returns a nerd font icon by name, e.g. "cpu-temp"
+		"icon": func(name string) (string, error) {
+			icon, ok := nerdfonts.ByName(name)
+			if !ok {
+				return "", errors.New("unknown icon: " + name)
+			}
+			return string(icon), nil
+		},
+	}
+}
+
+// lookupField resolves a dotted JSON field name, with numeric keys indexing
+// lists such as "processes.0.name"
+func lookupField(fields map[string]any, name string) (any, error) {
+	var value any = fields
+	for _, key := range strings.Split(name, ".") {
+		switch current := value.(type) {
+		case map[string]any:
+			var ok bool
+			if value, ok = current[key]; !ok {
+				return nil, errors.New("unknown field: " + name)
+			}
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(current) {
+				return nil, errors.New("unknown field: " + name)
+			}
+			value = current[index]
+		default:
+			return nil, errors.New("unknown field: " + name)
+		}
+	}
+	return value, nil
+}
+
+// verbValue converts a JSON number for an integer printf verb such as %d,
+// since JSON decoding turns every number into a float64
+func verbValue(verb string, value any) any {
+	number, ok := value.(float64)
+	if !ok || verb == "" || !strings.ContainsRune("dbcoOxXU", rune(verb[len(verb)-1])) {
+		return value
+	}
+	return int64(math.Round(number))
+}
+
+// formatValue formats a value for display: whole numbers without decimals,
+// other numbers with one decimal and lists comma-separated
+func formatValue(value any) string {
+	switch v := value.(type) {
+	case float64:
+		if v == math.Trunc(v) {
+			return strconv.FormatFloat(v, 'f', 0, 64)
+		}
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	case []any:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, formatValue(item))
+		}
+		return strings.Join(parts, ", ")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// toFloat converts the numeric types found in metrics structs
+func toFloat(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case float32:
+		return float64(v)
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		number, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+		return number
+	}
+}
+
+// formatByteCount formats a byte count using binary units
+func formatByteCount(bytes float64) string {
+	switch {
+	case bytes >= 1<<30:
+		return fmt.Sprintf("%.1f GiB", bytes/(1<<30))
+	case bytes >= 1<<20:
+		return fmt.Sprintf("%.0f MiB", bytes/(1<<20))
+	default:
+		return fmt.Sprintf("%.0f KiB", bytes/(1<<10))
+	}
+}
@@ -0,0 +1,47 @@
+// Package formatting pads numbers so the module keeps its width between updates
+package formatting
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FigureSpace is as wide as a digit in most fonts, so padded numbers line up
+const FigureSpace = "\u2007"
+
+// padding is the pad character set by SetFixedWidth, "" disables padding
+var padding string
+
+// SetFixedWidth pads numbers with pad in Number and Pad, "" disables it
+func SetFixedWidth(pad string) {
+	padding = pad
+}
+
+// FixedWidth reports whether numbers are padded
+func FixedWidth() bool {
+	return padding != ""
+}
+
+// Number formats value with the given decimals. In fixed-width mode it is
+// left-padded to the width of widest, the largest plausible value of the metric.
+func Number(value float64, decimals int, widest float64) string {
+	text := strconv.FormatFloat(value, 'f', decimals, 64)
+	return Pad(text, utf8.RuneCountInString(strconv.FormatFloat(widest, 'f', decimals, 64)))
+}
+
+// Pad left-pads text to width characters in fixed-width mode
+func Pad(text string, width int) string {
+	if padding == "" {
+		return text
+	}
+	return strings.Repeat(padding, max(width-utf8.RuneCountInString(text), 0)) + text
+}
+
+// PadRight right-pads text to width characters in fixed-width mode
+func PadRight(text string, width int) string {
+	if padding == "" {
+		return text
+	}
+	return text + strings.Repeat(padding, max(width-utf8.RuneCountInString(text), 0))
+}
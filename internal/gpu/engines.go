@@ -0,0 +1,220 @@
+// Package gpu provides per-engine GPU utilization
+package gpu
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// EngineUtilization is the busy percentage of each GPU engine. Engines the
+// source cannot tell apart are set to -1: gpu_metrics only reports graphics
+// and a combined video figure, fdinfo fills in the rest on request.
+type EngineUtilization struct {
+	GfxUtil      float64 `json:"gfx_util"`
+	ComputeUtil  float64 `json:"compute_util"`
+	EncodeUtil   float64 `json:"encode_util"` // VCN encode
+	DecodeUtil   float64 `json:"decode_util"` // VCN decode and JPEG
+	VideoUtil    float64 `json:"video_util"`  // VCN encode and decode
+	DMAUtil      float64 `json:"dma_util"`
+	EngineSource string  `json:"engine_source"` // gpu_metrics, fdinfo, both joined by "+" or empty when unknown
+}
+
+// engineNames lists the engines accepted by GetEngine
+var engineNames = []string{"gfx", "compute", "video", "encode", "decode", "dma"}
+
+// unknownEngines is the utilization reported when no source is available
+func unknownEngines() EngineUtilization {
+	return EngineUtilization{
+		GfxUtil:     -1,
+		ComputeUtil: -1,
+		EncodeUtil:  -1,
+		DecodeUtil:  -1,
+		VideoUtil:   -1,
+		DMAUtil:     -1,
+	}
+}
+
+// sampleEngines makes GetAllMetrics fill every engine from DRM fdinfo
+var sampleEngines bool
+
+// SetEngineSampling makes GetAllMetrics scan DRM fdinfo for the engines the
+// gpu_metrics table cannot report. Meant for the daemon: the scan walks every
+// /proc/<pid>/fd and its first sample waits minClientWindow.
+func SetEngineSampling(enabled bool) {
+	sampleEngines = enabled
+}
+
+// GetEngineUtilization returns the per-engine utilization of the selected GPU.
+// The gpu_metrics activity fields are used where present; the DRM fdinfo
+// counters of every client fill in the engines the table cannot tell apart,
+// compute from graphics and encode from decode. fdinfo only covers the
+// processes of the calling user.
+func GetEngineUtilization() (EngineUtilization, error) {
+	return engineUtilization(engineNames...)
+}
+
+// GetEngine returns the utilization of one engine: gfx, compute, video,
+// encode, decode or dma
+func GetEngine(engine string) (float64, error) {
+	if err := ValidateEngine(engine); err != nil {
+		return 0, err
+	}
+	engines, err := engineUtilization(engine)
+	if err != nil {
+		return 0, err
+	}
+	return engines.Engine(engine)
+}
+
+// engineUtilization reads the gpu_metrics activity fields, scanning fdinfo
+// only when one of the wanted engines is missing from the table
+func engineUtilization(wanted ...string) (EngineUtilization, error) {
+	if IsSuspended() {
+		return unknownEngines(), ErrSuspended
+	}
+
+	engines := unknownEngines()
+	table, tableErr := readMetricsTable()
+	if tableErr == nil {
+		engines = enginesFromTable(table)
+	}
+
+	missing := slices.ContainsFunc(wanted, func(engine string) bool {
+		_, err := engines.Engine(engine)
+		return err != nil
+	})
+	if !missing {
+		return engines, nil
+	}
+
+	fdinfo, err := enginesFromFdinfo()
+	if err != nil {
+		if tableErr != nil {
+			return unknownEngines(), errors.New("engine utilization not available: " + err.Error())
+		}
+		return engines, nil
+	}
+	return engines.merge(fdinfo), nil
+}
+
+// ValidateEngine checks an engine name accepted by GetEngine
+func ValidateEngine(engine string) error {
+	if slices.Contains(engineNames, engine) {
+		return nil
+	}
+	return errors.New("unknown engine " + engine + ", expected one of " + strings.Join(engineNames, ", "))
+}
+
+// Engine picks the utilization of one engine by name
+func (e EngineUtilization) Engine(engine string) (float64, error) {
+	var value float64
+	switch engine {
+	case "gfx":
+		value = e.GfxUtil
+	case "compute":
+		value = e.ComputeUtil
+	case "video":
+		value = e.VideoUtil
+	case "encode":
+		value = e.EncodeUtil
+	case "decode":
+		value = e.DecodeUtil
+	case "dma":
+		value = e.DMAUtil
+	default:
+		return 0, ValidateEngine(engine)
+	}
+
+	if value < 0 {
+		return 0, errors.New(engine + " utilization not available from " + e.EngineSource)
+	}
+	return value, nil
+}
+
+// enginesFromFdinfo sums the engine usage of every DRM client on the card
+func enginesFromFdinfo() (EngineUtilization, error) {
+	processes, err := GetProcesses(0)
+	if err != nil {
+		return unknownEngines(), err
+	}
+
+	engines := EngineUtilization{EngineSource: "fdinfo"}
+	for _, process := range processes {
+		for engine, percent := range process.Engines {
+			switch {
+			case engine == "gfx":
+				engines.GfxUtil += percent
+			case engine == "compute":
+				engines.ComputeUtil += percent
+			case engine == "dma":
+				engines.DMAUtil += percent
+			case strings.HasPrefix(engine, "enc"):
+				engines.EncodeUtil += percent
+			case strings.HasPrefix(engine, "dec"), engine == "jpeg":
+				engines.DecodeUtil += percent
+			}
+		}
+	}
+
+	engines.GfxUtil = min(engines.GfxUtil, 100)
+	engines.ComputeUtil = min(engines.ComputeUtil, 100)
+	engines.DMAUtil = min(engines.DMAUtil, 100)
+	engines.EncodeUtil = min(engines.EncodeUtil, 100)
+	engines.DecodeUtil = min(engines.DecodeUtil, 100)
+	engines.VideoUtil = min(engines.EncodeUtil+engines.DecodeUtil, 100)
+
+	return engines, nil
+}
+
+// merge fills the engines unknown to e from other
+func (e EngineUtilization) merge(other EngineUtilization) EngineUtilization {
+	for _, engine := range []struct {
+		value *float64
+		other float64
+	}{
+		{&e.GfxUtil, other.GfxUtil},
+		{&e.ComputeUtil, other.ComputeUtil},
+		{&e.EncodeUtil, other.EncodeUtil},
+		{&e.DecodeUtil, other.DecodeUtil},
+		{&e.VideoUtil, other.VideoUtil},
+		{&e.DMAUtil, other.DMAUtil},
+	} {
+		if *engine.value < 0 {
+			*engine.value = engine.other
+		}
+	}
+
+	if e.EngineSource == "" {
+		e.EngineSource = other.EngineSource
+	} else if other.EngineSource != "" {
+		e.EngineSource += "+" + other.EngineSource
+	}
+	return e
+}
+
+// withFdinfo fills the engines unknown to engines from DRM fdinfo when
+// engine sampling is enabled
+func withFdinfo(engines EngineUtilization) EngineUtilization {
+	if !sampleEngines {
+		return engines
+	}
+	if fdinfo, err := enginesFromFdinfo(); err == nil {
+		return engines.merge(fdinfo)
+	}
+	return engines
+}
+
+// enginesFromTable maps the gpu_metrics activity fields onto engines
+func enginesFromTable(table *metricsTable) EngineUtilization {
+	engines := unknownEngines()
+	if table.gfxActivity >= 0 {
+		engines.GfxUtil = float64(table.gfxActivity)
+		engines.EngineSource = "gpu_metrics"
+	}
+	if table.mmActivity >= 0 {
+		engines.VideoUtil = float64(table.mmActivity)
+		engines.EngineSource = "gpu_metrics"
+	}
+	return engines
+}
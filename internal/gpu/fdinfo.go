@@ -0,0 +1,291 @@
+// Package gpu provides per-process GPU usage from DRM fdinfo
+package gpu
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProcessUsage is the GPU usage of one DRM client over the sample window
+type ProcessUsage struct {
+	PID     int                `json:"pid"`
+	Name    string             `json:"name"`
+	Engines map[string]float64 `json:"engines"` // busy % per engine (gfx, compute, enc, dec, ...)
+	VRAM    uint64             `json:"vram"`    // bytes
+}
+
+// Busy returns the summed engine busy percentage of the process
+func (p ProcessUsage) Busy() float64 {
+	var busy float64
+	for _, percent := range p.Engines {
+		busy += percent
+	}
+	return busy
+}
+
+// drmClient is one DRM file description, as published in /proc/<pid>/fdinfo
+type drmClient struct {
+	pid        int
+	name       string
+	engines    map[string]uint64 // busy time in ns
+	capacities map[string]uint64 // rings per engine, 1 when not published
+	vram       uint64            // bytes
+}
+
+// clientSample holds the two most recent fdinfo snapshots used for engine deltas
+type clientSample struct {
+	prev, cur map[string]*drmClient
+	prevTaken time.Time
+	taken     time.Time
+}
+
+// minClientWindow is the shortest window engine usage is computed over
+const minClientWindow = 100 * time.Millisecond
+
+// lastClients keeps the previous fdinfo snapshot of each GPU, keyed by PCI
+// address, for long-running processes
+var lastClients = make(map[string]*clientSample)
+
+// GetProcesses returns the processes using the selected GPU, busiest first.
+// A limit of 0 returns every process.
+func GetProcesses(limit int) ([]ProcessUsage, error) {
+	if gpuPaths == nil || gpuPaths.PCI == "" {
+		return nil, errors.New("GPU PCI address not available")
+	}
+
+	sample, err := sampleClients(gpuPaths.PCI)
+	if err != nil {
+		return nil, err
+	}
+
+	window := float64(sample.taken.Sub(sample.prevTaken).Nanoseconds())
+	if window <= 0 {
+		return nil, errors.New("empty fdinfo sample window")
+	}
+
+	processes := make([]ProcessUsage, 0, len(sample.cur))
+	for id, client := range sample.cur {
+		usage := ProcessUsage{
+			PID:     client.pid,
+			Name:    client.name,
+			Engines: make(map[string]float64, len(client.engines)),
+			VRAM:    client.vram,
+		}
+
+		// Clients opened during the window have no earlier busy time to
+		// subtract, their lifetime total would read as 100%
+		prev := sample.prev[id]
+		for engine, busy := range client.engines {
+			if prev == nil {
+				usage.Engines[engine] = 0
+				continue
+			}
+			before := prev.engines[engine]
+			if busy < before {
+				continue
+			}
+			percent := float64(busy-before) / window / float64(client.capacities[engine]) * 100
+			usage.Engines[engine] = min(percent, 100)
+		}
+
+		processes = append(processes, usage)
+	}
+
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].Busy() != processes[j].Busy() {
+			return processes[i].Busy() > processes[j].Busy()
+		}
+		return processes[i].VRAM > processes[j].VRAM
+	})
+
+	if limit > 0 && len(processes) > limit {
+		processes = processes[:limit]
+	}
+
+	return processes, nil
+}
+
+// sampleClients returns two fdinfo snapshots at least minClientWindow apart,
+// reusing the previous snapshot when available
+func sampleClients(pci string) (*clientSample, error) {
+	last := lastClients[pci]
+	if last != nil && time.Since(last.taken) < minClientWindow {
+		return last, nil
+	}
+
+	var prev map[string]*drmClient
+	var prevTaken time.Time
+	if last != nil {
+		prev, prevTaken = last.cur, last.taken
+	} else {
+		clients, err := readClients(pci)
+		if err != nil {
+			return nil, err
+		}
+		prev, prevTaken = clients, time.Now()
+
+		time.Sleep(minClientWindow)
+	}
+
+	cur, err := readClients(pci)
+	if err != nil {
+		return nil, err
+	}
+
+	sample := &clientSample{
+		prev:      prev,
+		cur:       cur,
+		prevTaken: prevTaken,
+		taken:     time.Now(),
+	}
+	lastClients[pci] = sample
+	return sample, nil
+}
+
+// readClients collects every amdgpu DRM client of the given PCI device, keyed
+// by drm-client-id so file descriptors shared between processes count once
+func readClients(pci string) (map[string]*drmClient, error) {
+	pidDirs, err := filepath.Glob("/proc/[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+
+	clients := make(map[string]*drmClient)
+	for _, pidDir := range pidDirs {
+		pid, err := strconv.Atoi(filepath.Base(pidDir))
+		if err != nil {
+			continue
+		}
+
+		// Processes of other users are not readable, skip them quietly
+		fds, err := os.ReadDir(filepath.Join(pidDir, "fd"))
+		if err != nil {
+			continue
+		}
+
+		name := ""
+		for _, fd := range fds {
+			target, err := os.Readlink(filepath.Join(pidDir, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(target, "/dev/dri/") {
+				continue
+			}
+
+			id, client, err := readFdinfo(filepath.Join(pidDir, "fdinfo", fd.Name()), pci)
+			if err != nil || client == nil {
+				continue
+			}
+			if _, seen := clients[id]; seen {
+				continue
+			}
+
+			if name == "" {
+				if comm, err := os.ReadFile(filepath.Join(pidDir, "comm")); err == nil {
+					name = strings.TrimSpace(string(comm))
+				}
+			}
+			client.pid = pid
+			client.name = name
+			clients[id] = client
+		}
+	}
+
+	return clients, nil
+}
+
+// readFdinfo parses one DRM fdinfo file, returning a nil client when it
+// belongs to another driver or device
+func readFdinfo(path, pci string) (string, *drmClient, error) {
+	file, err := os.Open(path) // #nosec G304 - path is built from /proc entries
+	if err != nil {
+		return "", nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	client := &drmClient{
+		engines:    make(map[string]uint64),
+		capacities: make(map[string]uint64),
+	}
+	var id, driver, pdev string
+	var residentVRAM uint64
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "drm-driver":
+			driver = value
+		case key == "drm-pdev":
+			pdev = value
+		case key == "drm-client-id":
+			id = value
+		case strings.HasPrefix(key, "drm-engine-capacity-"):
+			if n, err := strconv.ParseUint(value, 10, 64); err == nil && n > 0 {
+				client.capacities[strings.TrimPrefix(key, "drm-engine-capacity-")] = n
+			}
+		case strings.HasPrefix(key, "drm-engine-"):
+			if ns, err := strconv.ParseUint(strings.TrimSuffix(value, " ns"), 10, 64); err == nil {
+				client.engines[strings.TrimPrefix(key, "drm-engine-")] = ns
+			}
+		case key == "drm-memory-vram":
+			client.vram = parseFdinfoSize(value)
+		case key == "drm-resident-vram":
+			residentVRAM = parseFdinfoSize(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", nil, err
+	}
+
+	if driver != "amdgpu" || pdev != pci || id == "" {
+		return "", nil, nil
+	}
+
+	// Newer kernels replaced drm-memory-* with drm-resident-*
+	if client.vram == 0 {
+		client.vram = residentVRAM
+	}
+	for engine := range client.engines {
+		if client.capacities[engine] == 0 {
+			client.capacities[engine] = 1
+		}
+	}
+
+	return pdev + "/" + id, client, nil
+}
+
+// parseFdinfoSize parses sizes such as "1024 KiB" into bytes
+func parseFdinfoSize(value string) uint64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+
+	size, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	if len(fields) > 1 {
+		switch fields[1] {
+		case "KiB":
+			size *= 1024
+		case "MiB":
+			size *= 1024 * 1024
+		case "GiB":
+			size *= 1024 * 1024 * 1024
+		}
+	}
+
+	return size
+}
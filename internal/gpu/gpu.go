@@ -13,34 +13,172 @@ import (
 
 // Metrics contains comprehensive GPU monitoring data
 type Metrics struct {
-	Power        float64 `json:"power"`
-	Temperature  int     `json:"temperature"`
-	Frequency    float64 `json:"frequency"`
-	Utilization  int     `json:"utilization"`
-	MemoryUsage  float64 `json:"memory_usage"`
-	FanSpeed     int     `json:"fan_speed"`
-	Voltage      float64 `json:"voltage"`
-	JunctionTemp int     `json:"junction_temp"`
-	MemoryTemp   int     `json:"memory_temp"`
-	PowerCap     float64 `json:"power_cap"`
+	Power        float64    `json:"power"`
+	Temperature  int        `json:"temperature"`
+	Frequency    float64    `json:"frequency"`
+	Utilization  int        `json:"utilization"`
+	MemoryUsage  float64    `json:"memory_usage"`
+	FanSpeed     int        `json:"fan_speed"`
+	Voltage      float64    `json:"voltage"`
+	JunctionTemp int        `json:"junction_temp"`
+	MemoryTemp   int        `json:"memory_temp"`
+	PowerCap     float64    `json:"power_cap"`
+	Card         string     `json:"card"`
+	PCI          string     `json:"pci"`
+	Name         string     `json:"name"`
+	Integrated   bool       `json:"integrated"`
+	Suspended    bool       `json:"suspended"`
+	SocClock     float64    `json:"soc_clock,omitempty"`
+	MemClock     float64    `json:"mem_clock,omitempty"`
+	CoreClocks   []float64  `json:"core_clocks,omitempty"`
+	FanPWM       int        `json:"fan_pwm,omitempty"`
+	Throttle     []string   `json:"throttle"`
+	VRAM         MemoryInfo `json:"vram"`
+	VisibleVRAM  MemoryInfo `json:"visible_vram"`
+	GTT          MemoryInfo `json:"gtt"`
+	EngineUtilization
+	Errors map[string]string `json:"errors,omitempty"` // unreadable fields by JSON name
 }
 
-var gpuPaths *discovery.GPUPaths
+// Err returns why the field with the given JSON name could not be read, or
+// nil when it holds a real value
+func (m *Metrics) Err(field string) error {
+	if msg, failed := m.Errors[field]; failed {
+		return errors.New(msg)
+	}
+	return nil
+}
+
+// setError records that a field could not be read, so the daemon reports it
+// as unavailable rather than as its zero value
+func (m *Metrics) setError(field string, err error) {
+	if m.Errors == nil {
+		m.Errors = make(map[string]string)
+	}
+	m.Errors[field] = err.Error()
+}
+
+// ErrSuspended is returned when the GPU is runtime-suspended (D3cold), since
+// reading its sensors would wake it up
+var ErrSuspended = errors.New("GPU is runtime-suspended")
+
+var (
+	devices  []*discovery.GPUPaths
+	gpuPaths *discovery.GPUPaths
+)
 
 // Initialize sets up the GPU package with discovered paths
 func Initialize(cache *discovery.PathCache) error {
-	if cache.GPU == nil {
+	if len(cache.GPU) == 0 {
 		return errors.New("no GPU paths found in cache")
 	}
-	gpuPaths = cache.GPU
+	devices = cache.GPU
+	gpuPaths = defaultDevice()
 	return nil
 }
 
+// defaultDevice prefers the first discrete GPU, which is what users usually
+// want to monitor on APU + dGPU systems
+func defaultDevice() *discovery.GPUPaths {
+	for _, dev := range devices {
+		if !dev.Integrated {
+			return dev
+		}
+	}
+	return devices[0]
+}
+
+// Devices returns every discovered GPU ordered by card index
+func Devices() []*discovery.GPUPaths {
+	return devices
+}
+
+// Selected returns the GPU the metric functions currently read from
+func Selected() *discovery.GPUPaths {
+	return gpuPaths
+}
+
+// Select chooses the GPU used by the metric functions. The selector is a
+// position in Devices(), a card name (card1), a PCI address (0000:03:00.0 or
+// 03:00.0), or igpu/dgpu. An empty selector keeps the default device.
+func Select(selector string) error {
+	if len(devices) == 0 {
+		if selector == "" {
+			return nil
+		}
+		return errors.New("no AMD GPU available")
+	}
+
+	selector = strings.ToLower(strings.TrimSpace(selector))
+	switch selector {
+	case "":
+		gpuPaths = defaultDevice()
+		return nil
+	case "igpu", "dgpu":
+		for _, dev := range devices {
+			if dev.Integrated == (selector == "igpu") {
+				gpuPaths = dev
+				return nil
+			}
+		}
+		return errors.New("no " + selector + " found")
+	}
+
+	if position, err := strconv.Atoi(selector); err == nil {
+		if position < 0 || position >= len(devices) {
+			return errors.New("GPU index out of range: " + selector)
+		}
+		gpuPaths = devices[position]
+		return nil
+	}
+
+	for _, dev := range devices {
+		if selector == cardName(dev) ||
+			selector == strings.ToLower(dev.PCI) ||
+			"0000:"+selector == strings.ToLower(dev.PCI) {
+			gpuPaths = dev
+			return nil
+		}
+	}
+
+	return errors.New("no GPU matches selector: " + selector)
+}
+
+// cardName returns the DRM card name (card1), or an empty string when unknown
+func cardName(dev *discovery.GPUPaths) string {
+	if dev.Card == "" {
+		return ""
+	}
+	return filepath.Base(dev.Card)
+}
+
+// IsSuspended reports whether the selected GPU is runtime-suspended. Reading
+// power/runtime_status is handled by the PCI core and does not wake the device.
+func IsSuspended() bool {
+	if gpuPaths == nil || gpuPaths.Device == "" {
+		return false
+	}
+
+	path := filepath.Clean(filepath.Join(gpuPaths.Device, "power", "runtime_status"))
+	if !strings.HasPrefix(path, "/sys/") || strings.Contains(path, "..") {
+		return false
+	}
+	data, err := os.ReadFile(path) // #nosec G304 - path is validated above
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(data)) == "suspended"
+}
+
 func readMetricFile(filename string) (string, error) {
 	if gpuPaths == nil || gpuPaths.HwMon == "" {
 		return "", errors.New("GPU hwmon path not available")
 	}
 
+	if IsSuspended() {
+		return "", ErrSuspended
+	}
+
 	path := filepath.Clean(filepath.Join(gpuPaths.HwMon, filename))
 	// Validate that the path is within expected system directory and doesn't contain path traversal
 	if !strings.HasPrefix(path, "/sys/") || strings.Contains(path, "..") {
@@ -54,20 +192,29 @@ func readMetricFile(filename string) (string, error) {
 }
 
 func readDeviceFile(filename string) (string, error) {
+	data, err := readDeviceBytes(filename)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readDeviceBytes reads a raw device file, such as the binary gpu_metrics table
+func readDeviceBytes(filename string) ([]byte, error) {
 	if gpuPaths == nil || gpuPaths.Device == "" {
-		return "", errors.New("GPU device path not available")
+		return nil, errors.New("GPU device path not available")
+	}
+
+	if IsSuspended() {
+		return nil, ErrSuspended
 	}
 
 	path := filepath.Clean(filepath.Join(gpuPaths.Device, filename))
 	// Validate that the path is within expected system directory and doesn't contain path traversal
 	if !strings.HasPrefix(path, "/sys/") || strings.Contains(path, "..") {
-		return "", errors.New("invalid system path")
+		return nil, errors.New("invalid system path")
 	}
-	data, err := os.ReadFile(path) // #nosec G304 - path is validated above
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(data)), nil
+	return os.ReadFile(path) // #nosec G304 - path is validated above
 }
 
 // GetPower returns GPU power consumption in watts
@@ -137,31 +284,12 @@ func GetUtilization() (int, error) {
 
 // GetMemoryUsage returns VRAM usage percentage
 func GetMemoryUsage() (float64, error) {
-	usedStr, err := readDeviceFile("mem_info_vram_used")
+	vram, err := GetVRAM()
 	if err != nil {
 		return 0, err
 	}
 
-	totalStr, err := readDeviceFile("mem_info_vram_total")
-	if err != nil {
-		return 0, err
-	}
-
-	used, err := strconv.ParseInt(usedStr, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	total, err := strconv.ParseInt(totalStr, 10, 64)
-	if err != nil {
-		return 0, err
-	}
-
-	if total == 0 {
-		return 0, errors.New("total VRAM is 0")
-	}
-
-	return float64(used) / float64(total) * 100, nil
+	return vram.Percent(), nil
 }
 
 // GetFanSpeed returns GPU fan speed in RPM
@@ -243,8 +371,24 @@ func GetPowerCap() (float64, error) {
 	return float64(capMicrowatts) / 1000000.0, nil
 }
 
-// GetAllMetrics collects all GPU metrics and returns them in a single structure
+// GetAllMetrics collects all GPU metrics and returns them in a single structure.
+// A runtime-suspended GPU is reported with Suspended set and no sensor values.
 func GetAllMetrics() (*Metrics, error) {
+	if IsSuspended() {
+		return &Metrics{
+			Card:       cardName(gpuPaths),
+			PCI:        gpuPaths.PCI,
+			Name:       gpuPaths.Name,
+			Integrated: gpuPaths.Integrated,
+			Suspended:  true,
+		}, nil
+	}
+
+	// Prefer the gpu_metrics table: one read instead of one per sensor
+	if table, err := readMetricsTable(); err == nil {
+		return metricsFromTable(table)
+	}
+
 	power, err := GetPower()
 	if err != nil {
 		return nil, err
@@ -265,47 +409,148 @@ func GetAllMetrics() (*Metrics, error) {
 		return nil, err
 	}
 
-	memUsage, err := GetMemoryUsage()
-	if err != nil {
-		memUsage = 0 // Don't fail on memory error, just set to 0
+	metrics := &Metrics{
+		Power:       power,
+		Temperature: temp,
+		Frequency:   freq,
+		Utilization: util,
+		Card:        cardName(gpuPaths),
+		PCI:         gpuPaths.PCI,
+		Name:        gpuPaths.Name,
+		Integrated:  gpuPaths.Integrated,
 	}
 
-	fanSpeed, err := GetFanSpeed()
-	if err != nil {
-		fanSpeed = 0 // Don't fail on fan speed error, just set to 0
+	// Optional sensors are left at 0, with the error recorded
+	if metrics.FanSpeed, err = GetFanSpeed(); err != nil {
+		metrics.setError("fan_speed", err)
+	}
+	if metrics.Voltage, err = GetVoltage(); err != nil {
+		metrics.setError("voltage", err)
+	}
+	if metrics.JunctionTemp, err = GetJunctionTemp(); err != nil {
+		metrics.setError("junction_temp", err)
+	}
+	if metrics.MemoryTemp, err = GetMemoryTemp(); err != nil {
+		metrics.setError("memory_temp", err)
+	}
+	if metrics.PowerCap, err = GetPowerCap(); err != nil {
+		metrics.setError("power_cap", err)
 	}
 
-	voltage, err := GetVoltage()
-	if err != nil {
-		voltage = 0 // Don't fail on voltage error, just set to 0
+	// An unreadable VRAM pool leaves the usage at 0
+	readMemoryPools(metrics)
+	metrics.MemoryUsage = metrics.VRAM.Percent()
+
+	// Without gpu_metrics only fdinfo, when sampled, knows the engines
+	metrics.EngineUtilization = withFdinfo(unknownEngines())
+
+	return metrics, nil
+}
+
+// metricsFromTable builds Metrics from a decoded gpu_metrics table, reading
+// the individual sysfs files only for values the table does not carry
+func metricsFromTable(table *metricsTable) (*Metrics, error) {
+	var err error
+	metrics := &Metrics{
+		Card:       cardName(gpuPaths),
+		PCI:        gpuPaths.PCI,
+		Name:       gpuPaths.Name,
+		Integrated: gpuPaths.Integrated,
 	}
 
-	junctionTemp, err := GetJunctionTemp()
-	if err != nil {
-		junctionTemp = 0 // Don't fail on junction temp error, just set to 0
+	if table.socketPower >= 0 {
+		metrics.Power = table.socketPower
+	} else if metrics.Power, err = GetPower(); err != nil {
+		return nil, err
 	}
 
-	memoryTemp, err := GetMemoryTemp()
-	if err != nil {
-		memoryTemp = 0 // Don't fail on memory temp error, just set to 0
+	if table.tempEdge >= 0 {
+		metrics.Temperature = int(table.tempEdge)
+	} else if metrics.Temperature, err = GetTemperature(); err != nil {
+		return nil, err
 	}
 
-	powerCap, err := GetPowerCap()
-	if err != nil {
-		powerCap = 0 // Don't fail on power cap error, just set to 0
-	}
-
-	return &Metrics{
-		Power:        power,
-		Temperature:  temp,
-		Frequency:    freq,
-		Utilization:  util,
-		MemoryUsage:  memUsage,
-		FanSpeed:     fanSpeed,
-		Voltage:      voltage,
-		JunctionTemp: junctionTemp,
-		MemoryTemp:   memoryTemp,
-		PowerCap:     powerCap,
-	}, nil
+	if table.gfxClock >= 0 {
+		metrics.Frequency = float64(table.gfxClock) / 1000
+	} else if metrics.Frequency, err = GetFrequency(); err != nil {
+		return nil, err
+	}
+
+	if table.gfxActivity >= 0 {
+		metrics.Utilization = table.gfxActivity
+	} else if metrics.Utilization, err = GetUtilization(); err != nil {
+		return nil, err
+	}
+
+	// VRAM usage and power cap are not part of the table
+	if metrics.PowerCap, err = GetPowerCap(); err != nil {
+		metrics.setError("power_cap", err)
+	}
+	readMemoryPools(metrics)
+	metrics.MemoryUsage = metrics.VRAM.Percent()
+
+	if table.fanSpeed >= 0 {
+		metrics.FanSpeed = table.fanSpeed
+	} else if metrics.FanSpeed, err = GetFanSpeed(); err != nil {
+		metrics.setError("fan_speed", err)
+	}
+
+	if table.voltageGfx >= 0 {
+		metrics.Voltage = table.voltageGfx
+	} else if metrics.Voltage, err = GetVoltage(); err != nil {
+		metrics.setError("voltage", err)
+	}
+
+	if table.tempHotspot >= 0 {
+		metrics.JunctionTemp = int(table.tempHotspot)
+	} else {
+		metrics.JunctionTemp = metrics.Temperature
+	}
+
+	if table.tempMem >= 0 {
+		metrics.MemoryTemp = int(table.tempMem)
+	} else {
+		metrics.MemoryTemp = metrics.Temperature
+	}
+
+	if table.socClock >= 0 {
+		metrics.SocClock = float64(table.socClock) / 1000
+	}
+	if table.memClock >= 0 {
+		metrics.MemClock = float64(table.memClock) / 1000
+	}
+	for _, clock := range table.coreClocks {
+		if clock >= 0 {
+			metrics.CoreClocks = append(metrics.CoreClocks, float64(clock)/1000)
+		}
+	}
+	if table.fanPWM >= 0 {
+		metrics.FanPWM = table.fanPWM
+	}
+	metrics.Throttle = decodeThrottle(table)
+
+	metrics.EngineUtilization = withFdinfo(enginesFromTable(table))
+
+	return metrics, nil
 }
 
+// GetAllDevicesMetrics collects metrics for every discovered GPU, skipping
+// devices that cannot be read
+func GetAllDevicesMetrics() ([]*Metrics, error) {
+	selected := gpuPaths
+	defer func() { gpuPaths = selected }()
+
+	var all []*Metrics
+	for _, dev := range devices {
+		gpuPaths = dev
+		if metrics, err := GetAllMetrics(); err == nil {
+			all = append(all, metrics)
+		}
+	}
+
+	if len(all) == 0 {
+		return nil, errors.New("no GPU metrics available")
+	}
+
+	return all, nil
+}
@@ -0,0 +1,82 @@
+// Package gpu provides VRAM and GTT memory reporting
+package gpu
+
+import (
+	"errors"
+	"strconv"
+)
+
+// MemoryInfo is the used and total size of one GPU memory pool in bytes
+type MemoryInfo struct {
+	Used  uint64 `json:"used"`
+	Total uint64 `json:"total"`
+}
+
+// Percent returns the used share of the pool
+func (m MemoryInfo) Percent() float64 {
+	if m.Total == 0 {
+		return 0
+	}
+	return float64(m.Used) / float64(m.Total) * 100
+}
+
+// GetVRAM returns the VRAM usage. On APUs this is the small carve-out
+// reserved by the firmware.
+func GetVRAM() (MemoryInfo, error) {
+	return readMemoryInfo("vram")
+}
+
+// GetVisibleVRAM returns the usage of the CPU-visible part of VRAM (the BAR)
+func GetVisibleVRAM() (MemoryInfo, error) {
+	return readMemoryInfo("vis_vram")
+}
+
+// GetGTT returns the usage of the GTT, the system memory mapped for the GPU.
+// On APUs most allocations live here.
+func GetGTT() (MemoryInfo, error) {
+	return readMemoryInfo("gtt")
+}
+
+// readMemoryInfo reads mem_info_<pool>_used and mem_info_<pool>_total
+func readMemoryInfo(pool string) (MemoryInfo, error) {
+	usedStr, err := readDeviceFile("mem_info_" + pool + "_used")
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	totalStr, err := readDeviceFile("mem_info_" + pool + "_total")
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	used, err := strconv.ParseUint(usedStr, 10, 64)
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	total, err := strconv.ParseUint(totalStr, 10, 64)
+	if err != nil {
+		return MemoryInfo{}, err
+	}
+
+	if total == 0 {
+		return MemoryInfo{}, errors.New("total " + pool + " is 0")
+	}
+
+	return MemoryInfo{Used: used, Total: total}, nil
+}
+
+// readMemoryPools fills the memory pools of metrics, leaving unreadable
+// pools empty with their error recorded
+func readMemoryPools(metrics *Metrics) {
+	var err error
+	if metrics.VRAM, err = GetVRAM(); err != nil {
+		metrics.setError("vram", err)
+	}
+	if metrics.VisibleVRAM, err = GetVisibleVRAM(); err != nil {
+		metrics.setError("visible_vram", err)
+	}
+	if metrics.GTT, err = GetGTT(); err != nil {
+		metrics.setError("gtt", err)
+	}
+}
@@ -0,0 +1,319 @@
+// Package gpu provides the decoder for the amdgpu gpu_metrics binary table
+package gpu
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+)
+
+// metricsTable is the decoded content of device/gpu_metrics. Numeric fields the
+// table revision does not carry, or that the firmware reports as unavailable,
+// are set to -1.
+type metricsTable struct {
+	formatRevision  uint8
+	contentRevision uint8
+
+	tempEdge    float64 // °C, gfx temperature on APUs
+	tempHotspot float64 // °C
+	tempMem     float64 // °C
+
+	gfxActivity int // %
+	mmActivity  int // % (UVD/VCN)
+
+	socketPower float64 // W
+
+	gfxClock   int // MHz
+	socClock   int // MHz
+	memClock   int // MHz
+	coreClocks []int
+
+	fanSpeed int // RPM
+	fanPWM   int
+
+	voltageGfx float64 // V
+
+	throttleStatus      uint32 // ASIC dependent
+	hasThrottleStatus   bool
+	indepThrottleStatus uint64 // ASIC independent, see throttle reasons
+	hasIndepThrottle    bool
+}
+
+// tableLayout gives the byte offsets of the fields we decode for one table
+// revision. A negative offset means the revision does not carry the field.
+type tableLayout struct {
+	size int
+
+	tempEdge, tempHotspot, tempMem int
+	centiDegrees                   bool
+
+	gfxActivity, mmActivity int
+
+	socketPower      int
+	socketPowerWide  bool // uint32 instead of uint16
+	milliwattsSocket bool
+
+	gfxClock, socClock, memClock int
+	coreClock, coreCount         int
+
+	fanSpeed, fanPWM int
+	voltageGfx       int
+
+	throttleStatus, indepThrottle int
+}
+
+// Layouts follow struct gpu_metrics_vX_Y in the kernel's kgd_pp_interface.h.
+// Format 1 is used by dGPUs, formats 2 and 3 by APUs. size is the number of
+// bytes needed to decode every field below.
+var tableLayouts = map[[2]uint8]tableLayout{
+	{1, 0}: {
+		size:           76,
+		tempEdge:       16,
+		tempHotspot:    18,
+		tempMem:        20,
+		gfxActivity:    28,
+		mmActivity:     32,
+		socketPower:    34,
+		gfxClock:       54,
+		socClock:       56,
+		memClock:       58,
+		coreClock:      -1,
+		fanSpeed:       72,
+		fanPWM:         -1,
+		voltageGfx:     -1,
+		throttleStatus: 68,
+		indepThrottle:  -1,
+	},
+	{1, 1}: v1Layout(96, -1, -1),
+	{1, 2}: v1Layout(104, -1, -1),
+	{1, 3}: v1Layout(120, 106, 112),
+	{2, 0}: {
+		size:             120,
+		tempEdge:         16,
+		tempHotspot:      -1,
+		tempMem:          -1,
+		coreCount:        8,
+		centiDegrees:     true,
+		gfxActivity:      40,
+		mmActivity:       42,
+		socketPower:      44,
+		milliwattsSocket: true,
+		gfxClock:         80,
+		socClock:         82,
+		memClock:         84,
+		coreClock:        92,
+		fanSpeed:         -1,
+		fanPWM:           116,
+		voltageGfx:       -1,
+		throttleStatus:   112,
+		indepThrottle:    -1,
+	},
+	{2, 1}: v2Layout(120, -1),
+	{2, 2}: v2Layout(128, 120),
+	{2, 3}: v2Layout(152, 120),
+	{2, 4}: v2Layout(164, 120),
+	{3, 0}: {
+		size:             260,
+		tempEdge:         4,
+		tempHotspot:      -1,
+		tempMem:          -1,
+		coreCount:        16,
+		centiDegrees:     true,
+		gfxActivity:      42,
+		mmActivity:       44,
+		socketPower:      112,
+		socketPowerWide:  true,
+		milliwattsSocket: true,
+		gfxClock:         174, // average, v3 has no current gfxclk
+		socClock:         176,
+		memClock:         186,
+		coreClock:        190,
+		fanSpeed:         -1,
+		fanPWM:           -1,
+		voltageGfx:       -1,
+		throttleStatus:   -1,
+		indepThrottle:    -1,
+	},
+}
+
+// v1Layout returns the layout shared by gpu_metrics v1.1 to v1.3
+func v1Layout(size, voltageGfx, indepThrottle int) tableLayout {
+	return tableLayout{
+		size:           size,
+		tempEdge:       4,
+		tempHotspot:    6,
+		tempMem:        8,
+		gfxActivity:    16,
+		mmActivity:     20,
+		socketPower:    22,
+		gfxClock:       54,
+		socClock:       56,
+		memClock:       58,
+		coreClock:      -1,
+		fanSpeed:       72,
+		fanPWM:         -1,
+		voltageGfx:     voltageGfx,
+		throttleStatus: 68,
+		indepThrottle:  indepThrottle,
+	}
+}
+
+// v2Layout returns the layout shared by gpu_metrics v2.1 to v2.4
+func v2Layout(size, indepThrottle int) tableLayout {
+	return tableLayout{
+		size:             size,
+		tempEdge:         4,
+		tempHotspot:      -1,
+		tempMem:          -1,
+		coreCount:        8,
+		centiDegrees:     true,
+		gfxActivity:      28,
+		mmActivity:       30,
+		socketPower:      40,
+		milliwattsSocket: true,
+		gfxClock:         76,
+		socClock:         78,
+		memClock:         80,
+		coreClock:        88,
+		fanSpeed:         -1,
+		fanPWM:           112,
+		voltageGfx:       -1,
+		throttleStatus:   108,
+		indepThrottle:    indepThrottle,
+	}
+}
+
+// readMetricsTable reads and decodes device/gpu_metrics for the selected GPU
+func readMetricsTable() (*metricsTable, error) {
+	data, err := readDeviceBytes("gpu_metrics")
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetricsTable(data)
+}
+
+// decodeMetricsTable decodes a gpu_metrics blob, rejecting unknown revisions
+func decodeMetricsTable(data []byte) (*metricsTable, error) {
+	if len(data) < 4 {
+		return nil, errors.New("gpu_metrics table too short")
+	}
+
+	structureSize := int(binary.LittleEndian.Uint16(data[0:2]))
+	format, content := data[2], data[3]
+
+	layout, ok := tableLayouts[[2]uint8{format, content}]
+	if !ok {
+		return nil, errors.New("unsupported gpu_metrics revision " +
+			strconv.Itoa(int(format)) + "." + strconv.Itoa(int(content)))
+	}
+	if len(data) < layout.size || structureSize < layout.size {
+		return nil, errors.New("gpu_metrics table smaller than expected for its revision")
+	}
+
+	r := tableReader(data)
+	table := &metricsTable{
+		formatRevision:  format,
+		contentRevision: content,
+	}
+
+	temp := func(offset int) float64 {
+		value := r.u16(offset)
+		if value < 0 {
+			return -1
+		}
+		if layout.centiDegrees {
+			return float64(value) / 100
+		}
+		return float64(value)
+	}
+
+	table.tempEdge = temp(layout.tempEdge)
+	table.tempHotspot = temp(layout.tempHotspot)
+	table.tempMem = temp(layout.tempMem)
+
+	table.gfxActivity = activity(r.u16(layout.gfxActivity))
+	table.mmActivity = activity(r.u16(layout.mmActivity))
+
+	var power int64
+	if layout.socketPowerWide {
+		power = r.u32(layout.socketPower)
+	} else {
+		power = int64(r.u16(layout.socketPower))
+	}
+	switch {
+	case power < 0:
+		table.socketPower = -1
+	case layout.milliwattsSocket:
+		table.socketPower = float64(power) / 1000
+	default:
+		table.socketPower = float64(power)
+	}
+
+	table.gfxClock = r.u16(layout.gfxClock)
+	table.socClock = r.u16(layout.socClock)
+	table.memClock = r.u16(layout.memClock)
+	for i := 0; layout.coreClock >= 0 && i < layout.coreCount; i++ {
+		table.coreClocks = append(table.coreClocks, r.u16(layout.coreClock+2*i))
+	}
+
+	table.fanSpeed = r.u16(layout.fanSpeed)
+	table.fanPWM = r.u16(layout.fanPWM)
+
+	if mv := r.u16(layout.voltageGfx); mv >= 0 {
+		table.voltageGfx = float64(mv) / 1000
+	} else {
+		table.voltageGfx = -1
+	}
+
+	if status := r.u32(layout.throttleStatus); status >= 0 {
+		table.throttleStatus = uint32(status)
+		table.hasThrottleStatus = true
+	}
+	if layout.indepThrottle >= 0 {
+		table.indepThrottleStatus = binary.LittleEndian.Uint64(data[layout.indepThrottle:])
+		table.hasIndepThrottle = table.indepThrottleStatus != ^uint64(0)
+	}
+
+	return table, nil
+}
+
+// activity normalises an activity field to a 0-100 percentage. Some APU
+// firmwares report centi-percent.
+func activity(value int) int {
+	switch {
+	case value < 0:
+		return -1
+	case value > 100 && value <= 10000:
+		return value / 100
+	case value > 100:
+		return -1
+	default:
+		return value
+	}
+}
+
+// tableReader reads little-endian fields, returning -1 for absent offsets and
+// for the all-ones value the firmware uses to mark unavailable sensors
+type tableReader []byte
+
+func (r tableReader) u16(offset int) int {
+	if offset < 0 || offset+2 > len(r) {
+		return -1
+	}
+	value := binary.LittleEndian.Uint16(r[offset:])
+	if value == 0xFFFF {
+		return -1
+	}
+	return int(value)
+}
+
+func (r tableReader) u32(offset int) int64 {
+	if offset < 0 || offset+4 > len(r) {
+		return -1
+	}
+	value := binary.LittleEndian.Uint32(r[offset:])
+	if value == 0xFFFFFFFF {
+		return -1
+	}
+	return int64(value)
+}
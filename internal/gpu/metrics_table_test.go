@@ -0,0 +1,188 @@
+package gpu
+
+import (
+	"encoding/binary"
+	"slices"
+	"testing"
+)
+
+// newTable returns a gpu_metrics blob of the given revision with every
+// field marked unavailable, as the firmware does for missing sensors
+func newTable(format, content uint8, size int) []byte {
+	data := make([]byte, size)
+	for i := 4; i < size; i++ {
+		data[i] = 0xFF
+	}
+	binary.LittleEndian.PutUint16(data[0:], uint16(size))
+	data[2], data[3] = format, content
+	return data
+}
+
+func put16(data []byte, offset int, value uint16) {
+	binary.LittleEndian.PutUint16(data[offset:], value)
+}
+
+func TestDecodeMetricsTableV1(t *testing.T) {
+	data := newTable(1, 3, 120)
+	put16(data, 4, 65)    // temperature_edge
+	put16(data, 6, 80)    // temperature_hotspot
+	put16(data, 8, 70)    // temperature_mem
+	put16(data, 16, 42)   // average_gfx_activity
+	put16(data, 20, 5)    // average_mm_activity
+	put16(data, 22, 150)  // average_socket_power, W
+	put16(data, 54, 2500) // current_gfxclk
+	put16(data, 56, 1200) // current_socclk
+	put16(data, 58, 1000) // current_uclk
+	binary.LittleEndian.PutUint32(data[68:], 0x4)
+	put16(data, 72, 1500) // current_fan_speed
+	put16(data, 106, 900) // voltage_gfx, mV
+	binary.LittleEndian.PutUint64(data[112:], 1<<33)
+
+	table, err := decodeMetricsTable(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.tempEdge != 65 || table.tempHotspot != 80 || table.tempMem != 70 {
+		t.Errorf("temperatures = %v/%v/%v, want 65/80/70", table.tempEdge, table.tempHotspot, table.tempMem)
+	}
+	if table.gfxActivity != 42 || table.mmActivity != 5 {
+		t.Errorf("activity = %d/%d, want 42/5", table.gfxActivity, table.mmActivity)
+	}
+	if table.socketPower != 150 {
+		t.Errorf("socketPower = %v, want 150", table.socketPower)
+	}
+	if table.gfxClock != 2500 || table.socClock != 1200 || table.memClock != 1000 {
+		t.Errorf("clocks = %d/%d/%d, want 2500/1200/1000", table.gfxClock, table.socClock, table.memClock)
+	}
+	if table.coreClocks != nil {
+		t.Errorf("coreClocks = %v, want none on a dGPU table", table.coreClocks)
+	}
+	if table.fanSpeed != 1500 || table.fanPWM != -1 {
+		t.Errorf("fan = %d RPM/%d PWM, want 1500/-1", table.fanSpeed, table.fanPWM)
+	}
+	if table.voltageGfx != 0.9 {
+		t.Errorf("voltageGfx = %v, want 0.9", table.voltageGfx)
+	}
+	if !table.hasThrottleStatus || table.throttleStatus != 0x4 {
+		t.Errorf("throttleStatus = %#x (%t), want 0x4", table.throttleStatus, table.hasThrottleStatus)
+	}
+	if !table.hasIndepThrottle || table.indepThrottleStatus != 1<<33 {
+		t.Errorf("indepThrottleStatus = %#x (%t), want 1<<33", table.indepThrottleStatus, table.hasIndepThrottle)
+	}
+}
+
+func TestDecodeMetricsTableV1Unavailable(t *testing.T) {
+	table, err := decodeMetricsTable(newTable(1, 1, 96))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.tempEdge != -1 || table.gfxActivity != -1 || table.socketPower != -1 || table.voltageGfx != -1 {
+		t.Errorf("unavailable fields decoded as %+v", table)
+	}
+	if table.hasThrottleStatus || table.hasIndepThrottle {
+		t.Errorf("throttle status reported without a value")
+	}
+}
+
+func TestDecodeMetricsTableV2(t *testing.T) {
+	data := newTable(2, 2, 128)
+	put16(data, 4, 4550)   // temperature_gfx, centi-degrees
+	put16(data, 28, 4200)  // average_gfx_activity, centi-percent on some firmwares
+	put16(data, 30, 10)    // average_mm_activity
+	put16(data, 40, 15000) // average_socket_power, mW
+	put16(data, 76, 2200)  // current_gfxclk
+	put16(data, 78, 800)   // current_socclk
+	put16(data, 80, 3000)  // current_uclk
+	for i := range 8 {
+		put16(data, 88+2*i, uint16(3000+100*i)) // current_coreclk
+	}
+	binary.LittleEndian.PutUint32(data[108:], 0)
+	put16(data, 112, 128) // fan_pwm
+	binary.LittleEndian.PutUint64(data[120:], 0)
+
+	table, err := decodeMetricsTable(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if table.tempEdge != 45.5 || table.tempHotspot != -1 || table.tempMem != -1 {
+		t.Errorf("temperatures = %v/%v/%v, want 45.5/-1/-1", table.tempEdge, table.tempHotspot, table.tempMem)
+	}
+	if table.gfxActivity != 42 || table.mmActivity != 10 {
+		t.Errorf("activity = %d/%d, want 42/10", table.gfxActivity, table.mmActivity)
+	}
+	if table.socketPower != 15 {
+		t.Errorf("socketPower = %v, want 15", table.socketPower)
+	}
+	if table.gfxClock != 2200 || table.socClock != 800 || table.memClock != 3000 {
+		t.Errorf("clocks = %d/%d/%d, want 2200/800/3000", table.gfxClock, table.socClock, table.memClock)
+	}
+	if want := []int{3000, 3100, 3200, 3300, 3400, 3500, 3600, 3700}; !slices.Equal(table.coreClocks, want) {
+		t.Errorf("coreClocks = %v, want %v", table.coreClocks, want)
+	}
+	if table.fanSpeed != -1 || table.fanPWM != 128 {
+		t.Errorf("fan = %d RPM/%d PWM, want -1/128", table.fanSpeed, table.fanPWM)
+	}
+	if !table.hasThrottleStatus || !table.hasIndepThrottle || table.indepThrottleStatus != 0 {
+		t.Errorf("throttle status = %#x/%#x, want both present and clear", table.throttleStatus, table.indepThrottleStatus)
+	}
+}
+
+func TestDecodeMetricsTableV21(t *testing.T) {
+	data := newTable(2, 1, 120)
+	put16(data, 40, 9500) // average_socket_power, mW
+
+	table, err := decodeMetricsTable(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.socketPower != 9.5 {
+		t.Errorf("socketPower = %v, want 9.5", table.socketPower)
+	}
+	if table.hasIndepThrottle {
+		t.Errorf("v2.1 has no ASIC independent throttle status")
+	}
+}
+
+func TestDecodeMetricsTableErrors(t *testing.T) {
+	undersized := newTable(2, 2, 128)
+	put16(undersized, 0, 100) // structure_size below the v2.2 layout
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"header only", []byte{120, 0, 1}},
+		{"unknown revision", newTable(9, 0, 128)},
+		{"unknown content revision", newTable(1, 9, 128)},
+		{"truncated v1.3", newTable(1, 3, 120)[:100]},
+		{"truncated v2.4", newTable(2, 4, 100)},
+		{"structure size too small", undersized},
+	}
+
+	for _, tt := range tests {
+		if table, err := decodeMetricsTable(tt.data); err == nil {
+			t.Errorf("%s: expected an error, got %+v", tt.name, table)
+		}
+	}
+}
+
+func TestActivity(t *testing.T) {
+	tests := []struct{ value, want int }{
+		{-1, -1},
+		{0, 0},
+		{100, 100},
+		{4200, 42},
+		{10000, 100},
+		{10001, -1},
+	}
+
+	for _, tt := range tests {
+		if got := activity(tt.value); got != tt.want {
+			t.Errorf("activity(%d) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
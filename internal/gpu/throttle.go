@@ -0,0 +1,96 @@
+// Package gpu provides throttle reason decoding for the gpu_metrics table
+package gpu
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// throttleReasons maps the ASIC independent indep_throttle_status bits
+// (SMU_THROTTLER_*_BIT in the kernel's amdgpu_smu.h) to readable names
+var throttleReasons = map[uint]string{
+	// Power
+	0: "PPT0",
+	1: "PPT1",
+	2: "PPT2",
+	3: "PPT3",
+	4: "SPL",
+	5: "FPPT",
+	6: "SPPT",
+	7: "SPPT APU",
+	// Current
+	16: "TDC GFX",
+	17: "TDC SOC",
+	18: "TDC MEM",
+	19: "TDC VDD",
+	20: "TDC CVIP",
+	21: "EDC CPU",
+	22: "EDC GFX",
+	23: "APCC",
+	// Temperature
+	32: "Thermal GPU",
+	33: "Thermal core",
+	34: "Thermal memory",
+	35: "Thermal edge",
+	36: "Thermal hotspot",
+	37: "Thermal SOC",
+	38: "Thermal VR GFX",
+	39: "Thermal VR SOC",
+	40: "Thermal VR MEM0",
+	41: "Thermal VR MEM1",
+	42: "Thermal liquid0",
+	43: "Thermal liquid1",
+	44: "VR hot 0",
+	45: "VR hot 1",
+	46: "Prochot CPU",
+	47: "Prochot GFX",
+	// Other
+	56: "PPM",
+	57: "FIT",
+}
+
+// decodeThrottle returns the active throttle reasons of a metrics table, or
+// nil when the table carries no throttle status at all
+func decodeThrottle(table *metricsTable) []string {
+	switch {
+	case table.hasIndepThrottle:
+		reasons := []string{}
+		bits := make([]uint, 0, len(throttleReasons))
+		for bit := range throttleReasons {
+			bits = append(bits, bit)
+		}
+		sort.Slice(bits, func(i, j int) bool { return bits[i] < bits[j] })
+
+		for _, bit := range bits {
+			if table.indepThrottleStatus&(1<<bit) != 0 {
+				reasons = append(reasons, throttleReasons[bit])
+			}
+		}
+		return reasons
+	case table.hasThrottleStatus:
+		// Older revisions only carry the ASIC specific bit layout
+		if table.throttleStatus == 0 {
+			return []string{}
+		}
+		return []string{fmt.Sprintf("ASIC status 0x%08x", table.throttleStatus)}
+	default:
+		return nil
+	}
+}
+
+// GetThrottleReasons returns the reasons the GPU is currently throttling, an
+// empty list when it is not throttling
+func GetThrottleReasons() ([]string, error) {
+	table, err := readMetricsTable()
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := decodeThrottle(table)
+	if reasons == nil {
+		return nil, errors.New("gpu_metrics table has no throttle status")
+	}
+
+	return reasons, nil
+}
@@ -6,13 +6,15 @@ type Icon string
 
 const (
 	// GPU Icons
-	GPUPower   Icon = "󰾲" // Power consumption
-	GPUTemp    Icon = "🌡" // Temperature
-	GPUFreq    Icon = "󰓅" // Frequency/clock speed
-	GPUUtil    Icon = "󰈸" // Usage/activity
-	GPUMemory  Icon = "󰍛" // Memory
-	GPUFan     Icon = "󰈐" // Fan speed
-	GPUVoltage Icon = "⚡" // Voltage
+	GPUPower    Icon = "󰾲" // Power consumption
+	GPUTemp     Icon = "🌡" // Temperature
+	GPUFreq     Icon = "󰓅" // Frequency/clock speed
+	GPUUtil     Icon = "󰈸" // Usage/activity
+	GPUMemory   Icon = "󰍛" // Memory
+	GPUFan      Icon = "󰈐" // Fan speed
+	GPUVoltage  Icon = "⚡" // Voltage
+	GPUAsleep   Icon = "󰒲" // Runtime-suspended
+	GPUThrottle Icon = "󰀦" // Throttling
 
 	// CPU Icons
 	CPUUsage    Icon = "󰘚" // CPU utilization
@@ -26,6 +28,7 @@ const (
 	CPUMinMax   Icon = "↕" // Range indicator
 	CPUIOwait   Icon = "⏸" // Time/wait
 	CPUPower    Icon = "󰾲" // Power consumption
+	CPUThrottle Icon = "󰀦" // Throttling
 
 	// AMD Pstate Icons
 	CPUPstateStatus        Icon = "" // Pstate driver status
@@ -38,3 +41,39 @@ const (
 func (i Icon) String() string {
 	return string(i)
 }
+
+// names maps the icon names accepted by templates to their icons
+var names = map[string]Icon{
+	"gpu-power":                 GPUPower,
+	"gpu-temp":                  GPUTemp,
+	"gpu-freq":                  GPUFreq,
+	"gpu-util":                  GPUUtil,
+	"gpu-memory":                GPUMemory,
+	"gpu-fan":                   GPUFan,
+	"gpu-voltage":               GPUVoltage,
+	"gpu-asleep":                GPUAsleep,
+	"gpu-throttle":              GPUThrottle,
+	"cpu-usage":                 CPUUsage,
+	"cpu-temp":                  CPUTemp,
+	"cpu-freq":                  CPUFreq,
+	"cpu-cores":                 CPUCores,
+	"cpu-memory":                CPUMemory,
+	"cpu-load":                  CPULoad,
+	"cpu-governor":              CPUGovernor,
+	"cpu-boost":                 CPUBoost,
+	"cpu-minmax":                CPUMinMax,
+	"cpu-iowait":                CPUIOwait,
+	"cpu-power":                 CPUPower,
+	"cpu-throttle":              CPUThrottle,
+	"cpu-pstate-status":         CPUPstateStatus,
+	"cpu-pstate-prefcore":       CPUPstatePrefcore,
+	"cpu-energy-perf":           CPUEnergyPerfPref,
+	"cpu-highest-perf":          CPUHighestPerf,
+	"cpu-lowest-nonlinear-freq": CPULowestNonlinearFreq,
+}
+
+// ByName returns the icon with the given name, e.g. "cpu-temp"
+func ByName(name string) (Icon, bool) {
+	icon, ok := names[name]
+	return icon, ok
+}
@@ -22,7 +22,7 @@ func main() {
 	cmd.SetPathCache(cache)
 
 	// Initialize GPU and CPU packages with discovered paths
-	if cache.GPU != nil {
+	if len(cache.GPU) > 0 {
 		if err := gpu.Initialize(cache); err != nil {
 			log.Printf("Warning: GPU initialization failed: %v", err)
 		}